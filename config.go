@@ -1,47 +1,128 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+	"users/domain/errors"
+	"users/infrastructure/dependencies"
 	"users/infrastructure/postgres"
 	"users/infrastructure/server"
+	"users/infrastructure/sqlite"
 )
 
 type Config struct {
-	Server *server.Config
-	DB     *postgres.Config
+	Server  *server.Config
+	Storage *dependencies.StorageConfig
 }
 
 func NewConfig() (*Config, error) {
+	adminKey, err := getSecret("ADMIN_API_KEY", "")
+	if err != nil {
+		return nil, err
+	}
+
 	serverConfig, err := server.NewConfig(
 		getInt("API_PORT", 8080),
 		get("PREFIX", "/app"),
 		getDuration("SERVER_IDLE_TIMEOUT", 1, time.Second),
 		getDuration("SERVER_READ_TIMEOUT", 5, time.Second),
 		getDuration("SERVER_WRITE_TIMEOUT", 10, time.Second),
+		adminKey,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	dbConfig, err := postgres.NewConfig(
+	driver := getDriver()
+	if err = dependencies.CheckDriver(driver); err != nil {
+		return nil, err
+	}
+
+	storageConfig := &dependencies.StorageConfig{Driver: driver}
+
+	// Only the settings of the selected driver are read, so that those of another one cannot stop it.
+	switch driver {
+	case dependencies.DriverPostgres:
+		if storageConfig.Postgres, err = newPostgresConfig(); err != nil {
+			return nil, err
+		}
+	case dependencies.DriverSQLite:
+		if storageConfig.SQLite, err = sqlite.NewConfig(get("DB_PATH", "./users.db")); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Config{
+		Server:  serverConfig,
+		Storage: storageConfig,
+	}, nil
+}
+
+func newPostgresConfig() (*postgres.Config, error) {
+	poolConfig, err := postgres.NewPoolConfig(
+		getInt("DB_MAX_CONNS", 0),
+		getInt("DB_MIN_CONNS", 0),
+		getDuration("DB_MAX_CONN_LIFETIME", 0, time.Second),
+		getDuration("DB_MAX_CONN_IDLE_TIME", 0, time.Second),
+		getDuration("DB_HEALTH_CHECK_PERIOD", 0, time.Second),
+		getDuration("DB_STATEMENT_TIMEOUT", 0, time.Millisecond),
+		get("DB_APPLICATION_NAME", "users-api"),
+		get("DB_SSLMODE", ""),
+		get("DB_SSLROOTCERT", ""),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn, err := getSecret("DATABASE_URL", "")
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := getSecret("DB_PASSWORD", "postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	replicas, err := getSecret("DB_REPLICA_URLS", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return postgres.NewConfig(
+		dsn,
 		get("DB_HOST", "localhost"),
 		get("DB_PORT", "5432"),
 		get("DB_NAME", "users"),
 		get("DB_USER", "postgres"),
-		get("DB_PASSWORD", "postgres"),
+		password,
 		getDuration("DB_TIMEOUT", 5, time.Second),
+		splitList(replicas),
+		getDuration("DB_REPLICA_CHECK_PERIOD", 5, time.Second),
+		poolConfig,
 	)
-	if err != nil {
-		return nil, err
+}
+
+// getDriver returns the storage driver. STORAGE, its name before STORAGE_DRIVER, is still read when
+// STORAGE_DRIVER is unset.
+func getDriver() string {
+	driver, ok := os.LookupEnv("STORAGE_DRIVER")
+	legacy, hasLegacy := os.LookupEnv("STORAGE")
+
+	switch {
+	case ok && hasLegacy:
+		log.Printf("Ignoring deprecated key: %q, %q is set", "STORAGE", "STORAGE_DRIVER")
+		return driver
+	case hasLegacy:
+		log.Printf("Key %q is deprecated, use %q instead", "STORAGE", "STORAGE_DRIVER")
+		return legacy
 	}
 
-	return &Config{
-		Server: serverConfig,
-		DB:     dbConfig,
-	}, nil
+	return get("STORAGE_DRIVER", dependencies.DriverPostgres)
 }
 
 func get(key string, defaultValue string) string {
@@ -52,6 +133,41 @@ func get(key string, defaultValue string) string {
 	return defaultValue
 }
 
+// getSecret returns the value of key, or the content of the file named by key_FILE as Docker and
+// Kubernetes mount secrets. An empty key, as compose sets for missing variables, gives way to the file.
+// Unlike get, it never logs the value.
+func getSecret(key string, defaultValue string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	path, fromFile := os.LookupEnv(key + "_FILE")
+
+	switch {
+	case fromFile && value != "":
+		return "", fmt.Errorf("%w: %s", errors.ConfigSecretConflict, key)
+	case fromFile:
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case ok:
+		return value, nil
+	}
+
+	log.Printf("Using default value for key: %q", key)
+	return defaultValue, nil
+}
+
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
 func getInt(key string, defaultValue int) int {
 	if value, ok := os.LookupEnv(key); ok {
 		if valueToInt, err := strconv.Atoi(value); err == nil {
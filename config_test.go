@@ -0,0 +1,117 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"users/domain/errors"
+)
+
+func TestGetSecret(t *testing.T) {
+	t.Run("on variable", func(t *testing.T) {
+		t.Setenv("TEST_SECRET", "from-env")
+
+		value, err := getSecret("TEST_SECRET", "default")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "from-env", value)
+	})
+
+	t.Run("on file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret")
+		assert.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+		t.Setenv("TEST_SECRET_FILE", path)
+
+		value, err := getSecret("TEST_SECRET", "default")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "from-file", value)
+	})
+
+	t.Run("on missing file", func(t *testing.T) {
+		t.Setenv("TEST_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		_, err := getSecret("TEST_SECRET", "default")
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("on variable and file", func(t *testing.T) {
+		t.Setenv("TEST_SECRET", "from-env")
+		t.Setenv("TEST_SECRET_FILE", "/run/secrets/test")
+
+		_, err := getSecret("TEST_SECRET", "default")
+
+		assert.ErrorIs(t, err, errors.ConfigSecretConflict)
+	})
+
+	t.Run("on empty variable and file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret")
+		assert.NoError(t, os.WriteFile(path, []byte("from-file"), 0o600))
+		t.Setenv("TEST_SECRET", "")
+		t.Setenv("TEST_SECRET_FILE", path)
+
+		value, err := getSecret("TEST_SECRET", "default")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "from-file", value)
+	})
+
+	t.Run("on default", func(t *testing.T) {
+		value, err := getSecret("TEST_SECRET", "default")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "default", value)
+	})
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Run("on sqlite with a broken postgres secret", func(t *testing.T) {
+		t.Setenv("STORAGE_DRIVER", "sqlite")
+		t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "users.db"))
+		t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+		t.Setenv("DB_REPLICA_URLS", "postgres://replica:notaport/users")
+
+		config, err := NewConfig()
+
+		if assert.NoError(t, err) {
+			assert.Nil(t, config.Storage.Postgres)
+			assert.NotNil(t, config.Storage.SQLite)
+		}
+	})
+
+	t.Run("on postgres with a broken postgres secret", func(t *testing.T) {
+		t.Setenv("STORAGE_DRIVER", "postgres")
+		t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		_, err := NewConfig()
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestGetDriver(t *testing.T) {
+	t.Run("on STORAGE_DRIVER", func(t *testing.T) {
+		t.Setenv("STORAGE_DRIVER", "sqlite")
+
+		assert.Equal(t, "sqlite", getDriver())
+	})
+
+	t.Run("on deprecated STORAGE", func(t *testing.T) {
+		t.Setenv("STORAGE", "memory")
+
+		assert.Equal(t, "memory", getDriver())
+	})
+
+	t.Run("on both", func(t *testing.T) {
+		t.Setenv("STORAGE_DRIVER", "sqlite")
+		t.Setenv("STORAGE", "memory")
+
+		assert.Equal(t, "sqlite", getDriver())
+	})
+
+	t.Run("on default", func(t *testing.T) {
+		assert.Equal(t, "postgres", getDriver())
+	})
+}
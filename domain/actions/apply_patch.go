@@ -0,0 +1,140 @@
+package actions
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+	"users/domain"
+	"users/domain/entities"
+	"users/domain/errors"
+)
+
+// maxPatchAttempts bounds how many times a patch is evaluated again after the user changed under it.
+const maxPatchAttempts = 3
+
+type ApplyPatch struct {
+	getByID domain.GetByID
+	update  domain.Update
+	tracer  trace.Tracer
+}
+
+func NewApplyPatch(getByID domain.GetByID, update domain.Update) (*ApplyPatch, error) {
+	return &ApplyPatch{
+		getByID: getByID,
+		update:  update,
+		tracer:  otel.Tracer("Action-ApplyPatch")}, nil
+}
+
+// Execute evaluates the operations against the current user and saves the result only if the user
+// has not changed in between, so the test operations always hold for the row that is written.
+func (action *ApplyPatch) Execute(ctx context.Context, id string, ops []entities.PatchOperation, version int64) (*entities.User, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-ApplyPatch-Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("action.patch.operations", len(ops)))
+
+	// The user read is written back, so it must not lag behind the primary.
+	tracerCtx = domain.WithPrimary(tracerCtx)
+
+	for attempt := 1; attempt <= maxPatchAttempts; attempt++ {
+		span.SetAttributes(attribute.Int("action.patch.attempts", attempt))
+
+		result, err := action.getByID(tracerCtx, []string{id})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result) == 0 {
+			return nil, errors.AppUserNotFound
+		}
+
+		user := result[0]
+
+		if version != entities.AnyVersion && user.Version != version {
+			return nil, errors.AppVersionMismatch
+		}
+
+		fields, err := applyOperations(user, ops)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) == 0 {
+			return user, nil
+		}
+
+		updated, err := action.update(tracerCtx, id, fields, user.Version)
+		if stdErrors.Is(err, errors.AppVersionMismatch) && version == entities.AnyVersion {
+			continue
+		}
+
+		return updated, err
+	}
+
+	return nil, errors.AppVersionMismatch
+}
+
+// applyOperations runs the operations in order, each one seeing the changes of the previous ones,
+// and returns the fields that end up modified.
+func applyOperations(user *entities.User, ops []entities.PatchOperation) (map[string]interface{}, error) {
+	values := fieldValues(user)
+	fields := make(map[string]interface{})
+
+	for i, op := range ops {
+		value := op.Value
+		if email, ok := value.(string); ok && op.Field == "email" {
+			value = entities.NormalizeEmail(email)
+		}
+
+		switch op.Op {
+		case entities.PatchTest:
+			if !equalValue(values[op.Field], value) {
+				return nil, fmt.Errorf("operation %d: %q does not match: %w", i, op.Field, errors.AppPatchTestFailed)
+			}
+		case entities.PatchReplace:
+			values[op.Field] = value
+			fields[op.Field] = value
+		case entities.PatchRemove:
+			values[op.Field] = nil
+			fields[op.Field] = nil
+		default:
+			return nil, fmt.Errorf("operation %d: unsupported operation %q", i, op.Op)
+		}
+	}
+
+	return fields, nil
+}
+
+func fieldValues(user *entities.User) map[string]interface{} {
+	values := map[string]interface{}{
+		"name":     user.Name,
+		"birth":    nil,
+		"email":    nil,
+		"location": nil,
+		"active":   user.Active,
+	}
+
+	if user.Birth != nil {
+		values["birth"] = *user.Birth
+	}
+	if user.Email != nil {
+		values["email"] = *user.Email
+	}
+	if user.Location != nil {
+		values["location"] = *user.Location
+	}
+
+	return values
+}
+
+func equalValue(a interface{}, b interface{}) bool {
+	if t, ok := a.(time.Time); ok {
+		other, ok := b.(time.Time)
+		return ok && t.Equal(other)
+	}
+	return a == b
+}
@@ -0,0 +1,31 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type Export struct {
+	export domain.Export
+	tracer trace.Tracer
+}
+
+func NewExport(export domain.Export) (*Export, error) {
+	return &Export{
+		export: export,
+		tracer: otel.Tracer("Action-Export")}, nil
+}
+
+func (action *Export) Execute(ctx context.Context, filter entities.UserFilter, sort []entities.SortKey, fn func(*entities.User) error) error {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-Export-Execute")
+	defer span.End()
+
+	if len(sort) == 0 {
+		sort = entities.DefaultSort
+	}
+
+	return action.export(tracerCtx, filter, sort, fn)
+}
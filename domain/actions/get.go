@@ -3,11 +3,17 @@ package actions
 import (
 	"context"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"users/domain"
 	"users/domain/entities"
 )
 
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
 type Get struct {
 	get    domain.Get
 	tracer trace.Tracer
@@ -19,9 +25,22 @@ func NewGet(get domain.Get) (*Get, error) {
 		tracer: otel.Tracer("Action-Get")}, nil
 }
 
-func (action *Get) Execute(ctx context.Context) ([]*entities.User, error) {
+func (action *Get) Execute(ctx context.Context, filter entities.UserFilter, page entities.PageRequest) (*entities.Page, error) {
 	tracerCtx, span := action.tracer.Start(ctx, "Action-Get-Execute")
 	defer span.End()
 
-	return action.get(tracerCtx)
+	switch {
+	case page.Limit <= 0:
+		page.Limit = defaultPageSize
+	case page.Limit > maxPageSize:
+		page.Limit = maxPageSize
+	}
+
+	if len(page.Sort) == 0 {
+		page.Sort = entities.DefaultSort
+	}
+
+	span.SetAttributes(attribute.Int("action.page.limit", page.Limit))
+
+	return action.get(tracerCtx, filter, page)
 }
@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type GetDeleted struct {
+	getDeleted domain.GetDeleted
+	tracer     trace.Tracer
+}
+
+func NewGetDeleted(getDeleted domain.GetDeleted) (*GetDeleted, error) {
+	return &GetDeleted{
+		getDeleted: getDeleted,
+		tracer:     otel.Tracer("Action-GetDeleted")}, nil
+}
+
+func (action *GetDeleted) Execute(ctx context.Context) ([]*entities.User, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-GetDeleted-Execute")
+	defer span.End()
+
+	return action.getDeleted(tracerCtx)
+}
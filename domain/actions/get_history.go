@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type GetHistory struct {
+	getHistory domain.GetHistory
+	tracer     trace.Tracer
+}
+
+func NewGetHistory(getHistory domain.GetHistory) (*GetHistory, error) {
+	return &GetHistory{
+		getHistory: getHistory,
+		tracer:     otel.Tracer("Action-GetHistory")}, nil
+}
+
+func (action *GetHistory) Execute(ctx context.Context, id string) ([]*entities.UserHistory, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-GetHistory-Execute")
+	defer span.End()
+
+	return action.getHistory(tracerCtx, id)
+}
@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+const importChunkSize = 500
+
+type Import struct {
+	saveBatch domain.SaveBatch
+	tracer    trace.Tracer
+}
+
+func NewImport(saveBatch domain.SaveBatch) (*Import, error) {
+	return &Import{
+		saveBatch: saveBatch,
+		tracer:    otel.Tracer("Action-Import")}, nil
+}
+
+// Execute creates the users in chunks, each one in its own transaction, and returns one result per user.
+// If a chunk cannot be written at all, its users and those of the following chunks report that error.
+func (action *Import) Execute(ctx context.Context, users []*entities.User) ([]*entities.BatchResult, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-Import-Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("action.import.size", len(users)))
+
+	results := make([]*entities.BatchResult, 0, len(users))
+
+	for start := 0; start < len(users); start += importChunkSize {
+		end := min(start+importChunkSize, len(users))
+
+		chunk, err := action.saveBatch(tracerCtx, users[start:end], false)
+		if err != nil {
+			span.RecordError(err)
+			for range users[start:] {
+				results = append(results, &entities.BatchResult{Err: err})
+			}
+			break
+		}
+
+		results = append(results, chunk...)
+	}
+
+	return results, nil
+}
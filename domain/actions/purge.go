@@ -0,0 +1,26 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+)
+
+type Purge struct {
+	purge  domain.Purge
+	tracer trace.Tracer
+}
+
+func NewPurge(purge domain.Purge) (*Purge, error) {
+	return &Purge{
+		purge:  purge,
+		tracer: otel.Tracer("Action-Purge")}, nil
+}
+
+func (action *Purge) Execute(ctx context.Context, id string) error {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-Purge-Execute")
+	defer span.End()
+
+	return action.purge(tracerCtx, id)
+}
@@ -5,34 +5,25 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"users/domain"
-	"users/domain/errors"
 )
 
 type Remove struct {
-	getByID domain.GetByID
-	remove  domain.Remove
-	tracer  trace.Tracer
+	unitOfWork domain.UnitOfWork
+	tracer     trace.Tracer
 }
 
-func NewRemove(getByID domain.GetByID, remove domain.Remove) (*Remove, error) {
+func NewRemove(unitOfWork domain.UnitOfWork) (*Remove, error) {
 	return &Remove{
-		getByID: getByID,
-		remove:  remove,
-		tracer:  otel.Tracer("Action-Remove")}, nil
+		unitOfWork: unitOfWork,
+		tracer:     otel.Tracer("Action-Remove")}, nil
 }
 
-func (action *Remove) Execute(ctx context.Context, id string) error {
+func (action *Remove) Execute(ctx context.Context, id string, version int64) error {
 	tracerCtx, span := action.tracer.Start(ctx, "Action-Remove-Execute")
 	defer span.End()
 
-	result, err := action.getByID(tracerCtx, []string{id})
-	if err != nil {
-		return err
-	}
-
-	if len(result) == 0 {
-		return errors.AppUserNotFound
-	}
-
-	return action.remove(tracerCtx, id)
+	// The transaction locks the user and reports errors.AppUserNotFound when it is missing or in the trash.
+	return action.unitOfWork.Do(tracerCtx, func(tx domain.Tx) error {
+		return tx.Remove(tracerCtx, id, version)
+	})
 }
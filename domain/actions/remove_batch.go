@@ -0,0 +1,32 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type RemoveBatch struct {
+	removeBatch domain.RemoveBatch
+	tracer      trace.Tracer
+}
+
+func NewRemoveBatch(removeBatch domain.RemoveBatch) (*RemoveBatch, error) {
+	return &RemoveBatch{
+		removeBatch: removeBatch,
+		tracer:      otel.Tracer("Action-RemoveBatch")}, nil
+}
+
+func (action *RemoveBatch) Execute(ctx context.Context, ids []string) (*entities.BatchChange, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-RemoveBatch-Execute")
+	defer span.End()
+
+	ids = uniqueIDs(ids)
+
+	span.SetAttributes(attribute.Int("action.batch.size", len(ids)))
+
+	return action.removeBatch(tracerCtx, ids)
+}
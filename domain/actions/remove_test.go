@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"users/domain"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+)
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantRemoved bool
+		wantErr     error
+	}{
+		{
+			name:        "on existing user",
+			wantRemoved: true,
+		},
+		{
+			name:    "on missing user",
+			err:     errorspkg.AppUserNotFound,
+			wantErr: errorspkg.AppUserNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			removed := false
+
+			unitOfWork := domain.NoopUnitOfWork{Tx: domain.Tx{
+				Remove: func(context.Context, string, int64) error {
+					if test.err != nil {
+						return test.err
+					}
+					removed = true
+					return nil
+				},
+			}}
+
+			action, _ := NewRemove(unitOfWork)
+			err := action.Execute(context.Background(), "1", entities.AnyVersion)
+
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantRemoved, removed)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type Restore struct {
+	restore domain.Restore
+	tracer  trace.Tracer
+}
+
+func NewRestore(restore domain.Restore) (*Restore, error) {
+	return &Restore{
+		restore: restore,
+		tracer:  otel.Tracer("Action-Restore")}, nil
+}
+
+func (action *Restore) Execute(ctx context.Context, id string) (*entities.User, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-Restore-Execute")
+	defer span.End()
+
+	return action.restore(tracerCtx, id)
+}
@@ -6,34 +6,24 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"users/domain"
 	"users/domain/entities"
-	"users/domain/errors"
 )
 
 type Save struct {
-	getByID domain.GetByID
-	save    domain.Save
-	tracer  trace.Tracer
+	save   domain.Save
+	tracer trace.Tracer
 }
 
-func NewSave(getByID domain.GetByID, save domain.Save) (*Save, error) {
+// NewSave creates the action without an existence check: the storage reports an existing ID as
+// errors.AppUserExists, which no check made beforehand could guarantee against concurrent saves.
+func NewSave(save domain.Save) (*Save, error) {
 	return &Save{
-		getByID: getByID,
-		save:    save,
-		tracer:  otel.Tracer("Action-Save")}, nil
+		save:   save,
+		tracer: otel.Tracer("Action-Save")}, nil
 }
 
 func (action *Save) Execute(ctx context.Context, user *entities.User) (*entities.User, error) {
 	tracerCtx, span := action.tracer.Start(ctx, "Action-Save-Execute")
 	defer span.End()
 
-	result, err := action.getByID(tracerCtx, []string{user.ID})
-	if err != nil {
-		return nil, err
-	}
-
-	if len(result) == 1 {
-		return nil, errors.AppUserExists
-	}
-
 	return action.save(tracerCtx, user)
 }
@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type SaveBatch struct {
+	saveBatch domain.SaveBatch
+	tracer    trace.Tracer
+}
+
+func NewSaveBatch(saveBatch domain.SaveBatch) (*SaveBatch, error) {
+	return &SaveBatch{
+		saveBatch: saveBatch,
+		tracer:    otel.Tracer("Action-SaveBatch")}, nil
+}
+
+// Execute creates every user and returns one result per user, in the same order.
+// In atomic mode nothing is written unless every user is created.
+func (action *SaveBatch) Execute(ctx context.Context, users []*entities.User, atomic bool) ([]*entities.BatchResult, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-SaveBatch-Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("action.batch.size", len(users)))
+	span.SetAttributes(attribute.Bool("action.batch.atomic", atomic))
+
+	if len(users) == 0 {
+		return []*entities.BatchResult{}, nil
+	}
+
+	return action.saveBatch(tracerCtx, users, atomic)
+}
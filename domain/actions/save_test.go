@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+)
+
+func TestSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		saveErr error
+		want    *entities.User
+		wantErr error
+	}{
+		{
+			name: "on new user",
+			want: &entities.User{ID: "1", Name: "test"},
+		},
+		{
+			name:    "on existing user",
+			saveErr: errorspkg.AppUserExists,
+			wantErr: errorspkg.AppUserExists,
+		},
+		{
+			name:    "on save error",
+			saveErr: errors.New("an error occurred"),
+			wantErr: errors.New("an error occurred"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			save := func(_ context.Context, user *entities.User) (*entities.User, error) {
+				calls++
+				if test.saveErr != nil {
+					return nil, test.saveErr
+				}
+				return user, nil
+			}
+
+			action, _ := NewSave(save)
+			got, err := action.Execute(context.Background(), &entities.User{ID: "1", Name: "test"})
+
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"users/domain"
+	"users/domain/entities"
+)
+
+const (
+	defaultSearchResults = 20
+	maxSearchResults     = 100
+)
+
+type Search struct {
+	search domain.Search
+	tracer trace.Tracer
+}
+
+func NewSearch(search domain.Search) (*Search, error) {
+	return &Search{
+		search: search,
+		tracer: otel.Tracer("Action-Search")}, nil
+}
+
+func (action *Search) Execute(ctx context.Context, query string, limit int) ([]*entities.User, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-Search-Execute")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []*entities.User{}, nil
+	}
+
+	switch {
+	case limit <= 0:
+		limit = defaultSearchResults
+	case limit > maxSearchResults:
+		limit = maxSearchResults
+	}
+
+	span.SetAttributes(attribute.Int("action.search.limit", limit))
+
+	return action.search(tracerCtx, query, limit)
+}
@@ -6,34 +6,32 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"users/domain"
 	"users/domain/entities"
-	"users/domain/errors"
 )
 
 type Update struct {
-	getByID domain.GetByID
-	update  domain.Update
-	tracer  trace.Tracer
+	unitOfWork domain.UnitOfWork
+	tracer     trace.Tracer
 }
 
-func NewUpdate(getByID domain.GetByID, update domain.Update) (*Update, error) {
+func NewUpdate(unitOfWork domain.UnitOfWork) (*Update, error) {
 	return &Update{
-		getByID: getByID,
-		update:  update,
-		tracer:  otel.Tracer("Action-Update")}, nil
+		unitOfWork: unitOfWork,
+		tracer:     otel.Tracer("Action-Update")}, nil
 }
 
-func (action *Update) Execute(ctx context.Context, id string, fields map[string]interface{}) (*entities.User, error) {
+func (action *Update) Execute(ctx context.Context, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
 	tracerCtx, span := action.tracer.Start(ctx, "Action-Update-Execute")
 	defer span.End()
 
-	result, err := action.getByID(tracerCtx, []string{id})
+	// The transaction locks the user and reports errors.AppUserNotFound when it is missing or in the trash.
+	var updated *entities.User
+	err := action.unitOfWork.Do(tracerCtx, func(tx domain.Tx) (err error) {
+		updated, err = tx.Update(tracerCtx, id, fields, version)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(result) == 0 {
-		return nil, errors.AppUserNotFound
-	}
-
-	return action.update(tracerCtx, id, fields)
+	return updated, nil
 }
@@ -0,0 +1,45 @@
+package actions
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type UpdateBatch struct {
+	updateBatch domain.UpdateBatch
+	tracer      trace.Tracer
+}
+
+func NewUpdateBatch(updateBatch domain.UpdateBatch) (*UpdateBatch, error) {
+	return &UpdateBatch{
+		updateBatch: updateBatch,
+		tracer:      otel.Tracer("Action-UpdateBatch")}, nil
+}
+
+func (action *UpdateBatch) Execute(ctx context.Context, ids []string, fields map[string]interface{}) (*entities.BatchChange, error) {
+	tracerCtx, span := action.tracer.Start(ctx, "Action-UpdateBatch-Execute")
+	defer span.End()
+
+	ids = uniqueIDs(ids)
+
+	span.SetAttributes(attribute.Int("action.batch.size", len(ids)))
+
+	return action.updateBatch(tracerCtx, ids, fields)
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence of each.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"users/domain"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+)
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    *entities.User
+		wantErr error
+	}{
+		{
+			name: "on existing user",
+			want: &entities.User{ID: "1", Name: "after", Version: 2},
+		},
+		{
+			name:    "on missing user",
+			err:     errorspkg.AppUserNotFound,
+			wantErr: errorspkg.AppUserNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			unitOfWork := domain.NoopUnitOfWork{Tx: domain.Tx{
+				Update: func(_ context.Context, id string, fields map[string]interface{}, _ int64) (*entities.User, error) {
+					if test.err != nil {
+						return nil, test.err
+					}
+					return &entities.User{ID: id, Name: fields["name"].(string), Version: 2}, nil
+				},
+			}}
+
+			action, _ := NewUpdate(unitOfWork)
+			got, err := action.Execute(context.Background(), "1", map[string]interface{}{"name": "after"}, 1)
+
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
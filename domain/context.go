@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx that carries the identity of whoever requested the change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a copy of ctx whose reads must see every committed write, so storages with
+// read replicas serve them from the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func ReadsFromPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,15 @@
+package entities
+
+// BatchResult is the outcome of a single item of a batch operation.
+// Exactly one of User and Err is set.
+type BatchResult struct {
+	User *User
+	Err  error
+}
+
+// BatchChange is the outcome of applying the same change to a list of users.
+// Users holds the changed users; NotFound the IDs that do not match any user.
+type BatchChange struct {
+	Users    []*User
+	NotFound []string
+}
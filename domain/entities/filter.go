@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+type UserFilter struct {
+	Active      *bool
+	Location    *string
+	EmailDomain *string
+	CreatedAt   TimeRange
+	UpdatedAt   TimeRange
+	Birth       TimeRange
+}
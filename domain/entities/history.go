@@ -0,0 +1,70 @@
+package entities
+
+import "time"
+
+const (
+	HistoryCreated  = "created"
+	HistoryUpdated  = "updated"
+	HistoryDeleted  = "deleted"
+	HistoryRestored = "restored"
+)
+
+type UserHistory struct {
+	ID            int64
+	UserID        string
+	Action        string
+	Before        *User
+	After         *User
+	ChangedFields []string
+	Actor         *string
+	TraceID       *string
+	CreatedAt     time.Time
+}
+
+// ChangedFields lists the user-visible fields whose value differs between both snapshots.
+// A nil snapshot is treated as a user with every field unset.
+func ChangedFields(before *User, after *User) []string {
+	if before == nil {
+		before = &User{}
+	}
+	if after == nil {
+		after = &User{}
+	}
+
+	fields := make([]string, 0)
+
+	if before.Name != after.Name {
+		fields = append(fields, "name")
+	}
+	if !equalTime(before.Birth, after.Birth) {
+		fields = append(fields, "birth")
+	}
+	if !equalString(before.Email, after.Email) {
+		fields = append(fields, "email")
+	}
+	if !equalString(before.Location, after.Location) {
+		fields = append(fields, "location")
+	}
+	if before.Active != after.Active {
+		fields = append(fields, "active")
+	}
+	if !equalTime(before.DeletedAt, after.DeletedAt) {
+		fields = append(fields, "deleted_at")
+	}
+
+	return fields
+}
+
+func equalString(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
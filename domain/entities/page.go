@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	sortTimeLayout = "2006-01-02T15:04:05.000000"
+	sortDateLayout = "2006-01-02"
+)
+
+type SortField string
+
+const (
+	SortByName      SortField = "name"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByBirth     SortField = "birth"
+	SortByEmail     SortField = "email"
+)
+
+type SortKey struct {
+	Field SortField
+	Desc  bool
+}
+
+var DefaultSort = []SortKey{{Field: SortByName}}
+
+// Cursor points after the last user of a page. It holds the sort it was built for, since its values
+// only make sense in that order.
+type Cursor struct {
+	Sort   []SortKey
+	Values []string
+	ID     string
+}
+
+type PageRequest struct {
+	Limit int
+	Sort  []SortKey
+	After *Cursor
+}
+
+type Page struct {
+	Users []*User
+	Next  *Cursor
+}
+
+// SortValue returns the value of the field the way it is compared when sorting:
+// missing values are replaced by the zero value of their type.
+func (u *User) SortValue(field SortField) string {
+	switch field {
+	case SortByName:
+		return u.Name
+	case SortByCreatedAt:
+		return u.CreatedAt.Format(sortTimeLayout)
+	case SortByUpdatedAt:
+		return u.UpdatedAt.Format(sortTimeLayout)
+	case SortByBirth:
+		if u.Birth == nil {
+			return time.Time{}.Format(sortDateLayout)
+		}
+		return u.Birth.Format(sortDateLayout)
+	case SortByEmail:
+		if u.Email == nil {
+			return ""
+		}
+		return *u.Email
+	}
+	return ""
+}
+
+func NewCursor(user *User, sort []SortKey) *Cursor {
+	values := make([]string, len(sort))
+	for i, key := range sort {
+		values[i] = user.SortValue(key.Field)
+	}
+	return &Cursor{Sort: sort, Values: values, ID: user.ID}
+}
+
+// Matches tells whether the cursor was built for sort and holds a valid value for each of its fields.
+func (c *Cursor) Matches(sort []SortKey) bool {
+	if FormatSort(c.Sort) != FormatSort(sort) || len(c.Values) != len(sort) {
+		return false
+	}
+
+	for i, key := range sort {
+		if !validSortValue(key.Field, c.Values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FormatSort writes sort the way the sort query parameter spells it, such as "name,-created_at".
+func FormatSort(sort []SortKey) string {
+	terms := make([]string, len(sort))
+	for i, key := range sort {
+		terms[i] = string(key.Field)
+		if key.Desc {
+			terms[i] = "-" + terms[i]
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+func validSortValue(field SortField, value string) bool {
+	var err error
+	switch field {
+	case SortByCreatedAt, SortByUpdatedAt:
+		_, err = time.Parse(sortTimeLayout, value)
+	case SortByBirth:
+		_, err = time.Parse(sortDateLayout, value)
+	}
+	return err == nil
+}
@@ -0,0 +1,15 @@
+package entities
+
+const (
+	PatchTest    = "test"
+	PatchReplace = "replace"
+	PatchRemove  = "remove"
+)
+
+// PatchOperation is a JSON Patch (RFC 6902) operation on a single user field. Value holds the types used
+// by update field maps: string for name, email and location, time.Time for birth, bool for active, or nil.
+type PatchOperation struct {
+	Op    string
+	Field string
+	Value interface{}
+}
@@ -1,6 +1,12 @@
 package entities
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// AnyVersion skips the version check of conditional updates and removals.
+const AnyVersion int64 = 0
 
 type User struct {
 	ID        string
@@ -11,4 +17,11 @@ type User struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Active    bool
+	Version   int64
+	DeletedAt *time.Time
+}
+
+// NormalizeEmail returns the form an email is stored in. Emails are unique regardless of case.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
@@ -1,15 +1,32 @@
 package errors
 
 const (
-	ServerInvalidPort   = AppError("server: invalid port number")
-	ServerMissingPrefix = AppError("server: missing prefix")
-	AppUserExists       = AppError("app: user already exists")
-	AppUserNotFound     = AppError("app: user not found")
-	PostgresMissingHost = AppError("postgres: missing host")
-	PostgresMissingPort = AppError("postgres: missing port")
-	PostgresMissingDB   = AppError("postgres: missing database")
-	PostgresMissingUser = AppError("postgres: missing username")
-	PostgresMissingPwd  = AppError("postgres: missing password")
+	ServerInvalidPort       = AppError("server: invalid port number")
+	ServerMissingPrefix     = AppError("server: missing prefix")
+	AppUserExists           = AppError("app: user already exists")
+	AppEmailTaken           = AppError("app: email is already taken by another user")
+	AppUserNotFound         = AppError("app: user not found")
+	AppUserNotDeleted       = AppError("app: user must be in the trash before it is purged")
+	AppAdminOnly            = AppError("app: missing or wrong admin key")
+	AppInvalidCursor        = AppError("app: cursor does not match the sort order")
+	AppVersionMismatch      = AppError("app: user has been modified by another request")
+	AppBatchAborted         = AppError("app: batch rolled back because another item failed")
+	AppPatchTestFailed      = AppError("app: patch test operation failed")
+	AppTimeout              = AppError("app: storage did not answer in time")
+	StorageUnknown          = AppError("storage: unknown storage driver")
+	PostgresMissingHost     = AppError("postgres: missing host")
+	PostgresMissingPort     = AppError("postgres: missing port")
+	PostgresMissingDB       = AppError("postgres: missing database")
+	PostgresMissingUser     = AppError("postgres: missing username")
+	PostgresMissingPwd      = AppError("postgres: missing password")
+	PostgresInvalidDSN      = AppError("postgres: invalid connection string")
+	ConfigSecretConflict    = AppError("config: a variable and its _FILE variant are both set")
+	PostgresInvalidReplica  = AppError("postgres: invalid replica connection string")
+	PostgresInvalidCheck    = AppError("postgres: replica check period must be positive")
+	PostgresInvalidPool     = AppError("postgres: invalid pool size")
+	PostgresInvalidDuration = AppError("postgres: pool durations cannot be negative")
+	PostgresInvalidSSLMode  = AppError("postgres: invalid sslmode")
+	SQLiteMissingPath       = AppError("sqlite: missing database path")
 )
 
 type AppError string
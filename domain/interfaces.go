@@ -5,12 +5,48 @@ import (
 	"users/domain/entities"
 )
 
-type Get func(context.Context) ([]*entities.User, error)
+type Get func(context.Context, entities.UserFilter, entities.PageRequest) (*entities.Page, error)
+
+type Export func(context.Context, entities.UserFilter, []entities.SortKey, func(*entities.User) error) error
+
+type Search func(context.Context, string, int) ([]*entities.User, error)
 
 type GetByID func(context.Context, []string) ([]*entities.User, error)
 
 type Save func(context.Context, *entities.User) (*entities.User, error)
 
-type Update func(context.Context, string, map[string]interface{}) (*entities.User, error)
+type SaveBatch func(context.Context, []*entities.User, bool) ([]*entities.BatchResult, error)
+
+type Update func(context.Context, string, map[string]interface{}, int64) (*entities.User, error)
+
+type UpdateBatch func(context.Context, []string, map[string]interface{}) (*entities.BatchChange, error)
+
+type Remove func(context.Context, string, int64) error
+
+type RemoveBatch func(context.Context, []string) (*entities.BatchChange, error)
+
+type GetDeleted func(context.Context) ([]*entities.User, error)
+
+type Restore func(context.Context, string) (*entities.User, error)
+
+type Purge func(context.Context, string) error
+
+type GetHistory func(context.Context, string) ([]*entities.UserHistory, error)
 
-type Remove func(context.Context, string) error
+// UserRepository holds the functions every storage provides to the actions.
+type UserRepository interface {
+	Get(context.Context, entities.UserFilter, entities.PageRequest) (*entities.Page, error)
+	Export(context.Context, entities.UserFilter, []entities.SortKey, func(*entities.User) error) error
+	Search(context.Context, string, int) ([]*entities.User, error)
+	GetByID(context.Context, []string) ([]*entities.User, error)
+	Save(context.Context, *entities.User) (*entities.User, error)
+	SaveBatch(context.Context, []*entities.User, bool) ([]*entities.BatchResult, error)
+	Update(context.Context, string, map[string]interface{}, int64) (*entities.User, error)
+	UpdateBatch(context.Context, []string, map[string]interface{}) (*entities.BatchChange, error)
+	Remove(context.Context, string, int64) error
+	RemoveBatch(context.Context, []string) (*entities.BatchChange, error)
+	GetDeleted(context.Context) ([]*entities.User, error)
+	Restore(context.Context, string) (*entities.User, error)
+	Purge(context.Context, string) error
+	GetHistory(context.Context, string) ([]*entities.UserHistory, error)
+}
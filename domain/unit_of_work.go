@@ -0,0 +1,27 @@
+package domain
+
+import "context"
+
+// Tx holds the repository functions bound to a single transaction.
+type Tx struct {
+	GetByID GetByID
+	Save    Save
+	Update  Update
+	Remove  Remove
+}
+
+// UnitOfWork groups several repository calls into a single transaction.
+type UnitOfWork interface {
+	// Do runs fn with the functions of a new transaction, which is committed only if fn succeeds.
+	Do(ctx context.Context, fn func(Tx) error) error
+}
+
+// NoopUnitOfWork runs the callback with its functions as they are, without any transaction.
+// It suits storages that are already consistent on every call, such as in-memory ones used by tests.
+type NoopUnitOfWork struct {
+	Tx Tx
+}
+
+func (u NoopUnitOfWork) Do(_ context.Context, fn func(Tx) error) error {
+	return fn(u.Tx)
+}
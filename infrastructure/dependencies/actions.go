@@ -2,55 +2,128 @@ package dependencies
 
 import (
 	"context"
+	"users/domain"
 	"users/domain/actions"
 	"users/domain/entities"
-	"users/infrastructure/postgres"
 )
 
 type Actions struct {
-	Get     func(context.Context) ([]*entities.User, error)
-	GetByID func(context.Context, []string) ([]*entities.User, error)
-	Save    func(context.Context, *entities.User) (*entities.User, error)
-	Update  func(context.Context, string, map[string]interface{}) (*entities.User, error)
-	Remove  func(context.Context, string) error
+	Get         func(context.Context, entities.UserFilter, entities.PageRequest) (*entities.Page, error)
+	Export      func(context.Context, entities.UserFilter, []entities.SortKey, func(*entities.User) error) error
+	Search      func(context.Context, string, int) ([]*entities.User, error)
+	GetByID     func(context.Context, []string) ([]*entities.User, error)
+	Save        func(context.Context, *entities.User) (*entities.User, error)
+	Import      func(context.Context, []*entities.User) ([]*entities.BatchResult, error)
+	SaveBatch   func(context.Context, []*entities.User, bool) ([]*entities.BatchResult, error)
+	Update      func(context.Context, string, map[string]interface{}, int64) (*entities.User, error)
+	UpdateBatch func(context.Context, []string, map[string]interface{}) (*entities.BatchChange, error)
+	ApplyPatch  func(context.Context, string, []entities.PatchOperation, int64) (*entities.User, error)
+	Remove      func(context.Context, string, int64) error
+	RemoveBatch func(context.Context, []string) (*entities.BatchChange, error)
+	GetDeleted  func(context.Context) ([]*entities.User, error)
+	Restore     func(context.Context, string) (*entities.User, error)
+	Purge       func(context.Context, string) error
+	GetHistory  func(context.Context, string) ([]*entities.UserHistory, error)
 }
 
-func NewActions(postgresClient *postgres.Client) (*Actions, error) {
-	postgresRepo, err := postgres.NewRepository(postgresClient)
+// NewActions links the actions to a storage.
+func NewActions(repo domain.UserRepository, unitOfWork domain.UnitOfWork) (*Actions, error) {
+	get, err := actions.NewGet(repo.Get)
 	if err != nil {
 		return nil, err
 	}
 
-	get, err := actions.NewGet(postgresRepo.Get)
+	search, err := actions.NewSearch(repo.Search)
 	if err != nil {
 		return nil, err
 	}
 
-	getByID, err := actions.NewGetByID(postgresRepo.GetByID)
+	getByID, err := actions.NewGetByID(repo.GetByID)
 	if err != nil {
 		return nil, err
 	}
 
-	save, err := actions.NewSave(postgresRepo.GetByID, postgresRepo.Save)
+	save, err := actions.NewSave(repo.Save)
 	if err != nil {
 		return nil, err
 	}
 
-	update, err := actions.NewUpdate(postgresRepo.GetByID, postgresRepo.Update)
+	update, err := actions.NewUpdate(unitOfWork)
 	if err != nil {
 		return nil, err
 	}
 
-	remove, err := actions.NewRemove(postgresRepo.GetByID, postgresRepo.Remove)
+	remove, err := actions.NewRemove(unitOfWork)
+	if err != nil {
+		return nil, err
+	}
+
+	getDeleted, err := actions.NewGetDeleted(repo.GetDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	restore, err := actions.NewRestore(repo.Restore)
+	if err != nil {
+		return nil, err
+	}
+
+	purge, err := actions.NewPurge(repo.Purge)
+	if err != nil {
+		return nil, err
+	}
+
+	getHistory, err := actions.NewGetHistory(repo.GetHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	saveBatch, err := actions.NewSaveBatch(repo.SaveBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	updateBatch, err := actions.NewUpdateBatch(repo.UpdateBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	removeBatch, err := actions.NewRemoveBatch(repo.RemoveBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := actions.NewExport(repo.Export)
+	if err != nil {
+		return nil, err
+	}
+
+	importUsers, err := actions.NewImport(repo.SaveBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	applyPatch, err := actions.NewApplyPatch(repo.GetByID, repo.Update)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Actions{
-		Get:     get.Execute,
-		GetByID: getByID.Execute,
-		Save:    save.Execute,
-		Update:  update.Execute,
-		Remove:  remove.Execute,
+		Get:         get.Execute,
+		Export:      export.Execute,
+		Search:      search.Execute,
+		GetByID:     getByID.Execute,
+		Save:        save.Execute,
+		Import:      importUsers.Execute,
+		SaveBatch:   saveBatch.Execute,
+		Update:      update.Execute,
+		UpdateBatch: updateBatch.Execute,
+		ApplyPatch:  applyPatch.Execute,
+		Remove:      remove.Execute,
+		RemoveBatch: removeBatch.Execute,
+		GetDeleted:  getDeleted.Execute,
+		Restore:     restore.Execute,
+		Purge:       purge.Execute,
+		GetHistory:  getHistory.Execute,
 	}, nil
 }
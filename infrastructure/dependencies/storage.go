@@ -0,0 +1,148 @@
+package dependencies
+
+import (
+	"fmt"
+	"slices"
+	"users/domain"
+	"users/domain/errors"
+	"users/infrastructure/memory"
+	"users/infrastructure/postgres"
+	"users/infrastructure/sqlite"
+)
+
+// Drivers of the storages registered by default.
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+	DriverSQLite   = "sqlite"
+)
+
+// StorageConfig selects a storage by its driver and holds its settings. The settings of the other
+// drivers are nil.
+type StorageConfig struct {
+	Driver   string
+	Postgres *postgres.Config
+	SQLite   *sqlite.Config
+}
+
+// String describes the settings of the selected driver without their secrets, so that they can be logged.
+func (c *StorageConfig) String() string {
+	switch {
+	case c.Driver == DriverPostgres && c.Postgres != nil:
+		return fmt.Sprintf("driver=%s %s", c.Driver, c.Postgres)
+	case c.Driver == DriverSQLite && c.SQLite != nil:
+		return fmt.Sprintf("driver=%s path=%q", c.Driver, c.SQLite.Path)
+	}
+	return "driver=" + c.Driver
+}
+
+// Storage is an open storage the actions can be linked to.
+type Storage struct {
+	Repository domain.UserRepository
+	UnitOfWork domain.UnitOfWork
+	// Close releases the resources of the storage.
+	Close func()
+}
+
+// StorageFactory opens a storage from its settings.
+type StorageFactory func(*StorageConfig) (*Storage, error)
+
+var storages = map[string]StorageFactory{
+	DriverPostgres: openPostgres,
+	DriverMemory:   openMemory,
+	DriverSQLite:   openSQLite,
+}
+
+// RegisterStorage makes a storage available under driver, replacing any storage registered
+// under the same driver. It is meant to be called before the configuration is read.
+func RegisterStorage(driver string, factory StorageFactory) {
+	storages[driver] = factory
+}
+
+// Drivers returns the drivers of the registered storages, sorted.
+func Drivers() []string {
+	drivers := make([]string, 0, len(storages))
+	for driver := range storages {
+		drivers = append(drivers, driver)
+	}
+	slices.Sort(drivers)
+	return drivers
+}
+
+// CheckDriver fails if no storage is registered under driver.
+func CheckDriver(driver string) error {
+	if _, ok := storages[driver]; !ok {
+		return fmt.Errorf("%w %q, expected one of %v", errors.StorageUnknown, driver, Drivers())
+	}
+	return nil
+}
+
+// OpenStorage opens the storage selected by the driver of config.
+func OpenStorage(config *StorageConfig) (*Storage, error) {
+	if err := CheckDriver(config.Driver); err != nil {
+		return nil, err
+	}
+	return storages[config.Driver](config)
+}
+
+func openPostgres(config *StorageConfig) (*Storage, error) {
+	client, err := postgres.NewClient(config.Postgres)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if err = client.Migrate(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("database migration error: %w", err)
+	}
+
+	repo, err := postgres.NewRepository(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	unitOfWork, err := postgres.NewUnitOfWork(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &Storage{Repository: repo, UnitOfWork: unitOfWork, Close: client.Close}, nil
+}
+
+func openSQLite(config *StorageConfig) (*Storage, error) {
+	client, err := sqlite.NewClient(config.SQLite)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if err = client.Migrate(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("database migration error: %w", err)
+	}
+
+	repo, err := sqlite.NewRepository(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	unitOfWork, err := sqlite.NewUnitOfWork(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &Storage{Repository: repo, UnitOfWork: unitOfWork, Close: client.Close}, nil
+}
+
+// openMemory opens an in-memory storage, which starts empty on every run.
+func openMemory(_ *StorageConfig) (*Storage, error) {
+	repo, err := memory.NewRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Storage{Repository: repo, UnitOfWork: repo.UnitOfWork(), Close: func() {}}, nil
+}
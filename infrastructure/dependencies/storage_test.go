@@ -0,0 +1,39 @@
+package dependencies
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"users/domain"
+	"users/domain/errors"
+	"users/infrastructure/memory"
+)
+
+func TestOpenStorage(t *testing.T) {
+	repo, err := memory.NewRepository()
+	require.NoError(t, err)
+
+	closed := false
+	RegisterStorage("fake", func(config *StorageConfig) (*Storage, error) {
+		return &Storage{Repository: repo, UnitOfWork: domain.NoopUnitOfWork{}, Close: func() { closed = true }}, nil
+	})
+	t.Cleanup(func() { delete(storages, "fake") })
+
+	assert.Equal(t, []string{"fake", DriverMemory, DriverPostgres, DriverSQLite}, Drivers())
+
+	storage, err := OpenStorage(&StorageConfig{Driver: "fake"})
+	require.NoError(t, err)
+	assert.Same(t, repo, storage.Repository)
+
+	_, err = NewActions(storage.Repository, storage.UnitOfWork)
+	assert.NoError(t, err)
+
+	storage.Close()
+	assert.True(t, closed)
+}
+
+func TestOpenStorageUnknownDriver(t *testing.T) {
+	_, err := OpenStorage(&StorageConfig{Driver: "mongo"})
+	assert.ErrorIs(t, err, errors.StorageUnknown)
+	assert.EqualError(t, err, "storage: unknown storage driver \"mongo\", expected one of [memory postgres sqlite]")
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"users/domain/entities"
+)
+
+// sortFields are the fields users can be sorted by, as in the postgres repository.
+var sortFields = map[entities.SortField]bool{
+	entities.SortByName:      true,
+	entities.SortByCreatedAt: true,
+	entities.SortByUpdatedAt: true,
+	entities.SortByBirth:     true,
+	entities.SortByEmail:     true,
+}
+
+func checkSort(sort []entities.SortKey) error {
+	for _, key := range sort {
+		if !sortFields[key.Field] {
+			return fmt.Errorf("unknown sort field %q", key.Field)
+		}
+	}
+	return nil
+}
+
+func matches(user *entities.User, filter entities.UserFilter) bool {
+	if user.DeletedAt != nil {
+		return false
+	}
+
+	if filter.Active != nil && user.Active != *filter.Active {
+		return false
+	}
+
+	if filter.Location != nil && (user.Location == nil || !strings.EqualFold(*user.Location, *filter.Location)) {
+		return false
+	}
+
+	if filter.EmailDomain != nil && (user.Email == nil || !strings.EqualFold(emailDomain(*user.Email), *filter.EmailDomain)) {
+		return false
+	}
+
+	return inRange(&user.CreatedAt, filter.CreatedAt) && inRange(&user.UpdatedAt, filter.UpdatedAt) && inRange(user.Birth, filter.Birth)
+}
+
+// emailDomain returns the part after the first '@', like split_part(email, '@', 2).
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(email, "@")
+	domain, _, _ = strings.Cut(domain, "@")
+	return domain
+}
+
+// inRange tells whether value is within both bounds, which are inclusive. A missing value is never
+// within a range that has a bound.
+func inRange(value *time.Time, bounds entities.TimeRange) bool {
+	if bounds.From == nil && bounds.To == nil {
+		return true
+	}
+
+	if value == nil {
+		return false
+	}
+
+	if bounds.From != nil && value.Before(*bounds.From) {
+		return false
+	}
+
+	if bounds.To != nil && value.After(*bounds.To) {
+		return false
+	}
+
+	return true
+}
+
+// compareUsers orders users by the sort keys and then by ID, comparing the same values
+// a cursor holds byte by byte.
+func compareUsers(a *entities.User, b *entities.User, sort []entities.SortKey) int {
+	return compareToCursor(a, entities.NewCursor(b, sort), sort)
+}
+
+func compareToCursor(user *entities.User, cursor *entities.Cursor, sort []entities.SortKey) int {
+	for i, key := range sort {
+		result := strings.Compare(user.SortValue(key.Field), cursor.Values[i])
+		if key.Desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return strings.Compare(user.ID, cursor.ID)
+}
+
+func sortUsers(users []*entities.User, sort []entities.SortKey) {
+	slices.SortFunc(users, func(a *entities.User, b *entities.User) int {
+		return compareUsers(a, b, sort)
+	})
+}
@@ -0,0 +1,602 @@
+package memory
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"users/domain"
+	"users/domain/entities"
+	domainErrors "users/domain/errors"
+)
+
+// Repository keeps the users in memory with the semantics of the postgres repository: emails are
+// unique regardless of case, removed users stay in the trash, and every change advances updated_at and
+// the version and is recorded in the history. It differs in two ways: search matches substrings instead
+// of trigrams, and names and emails are sorted byte by byte, like the C collation, where the database
+// sorts them by its own collation; "Zoe" comes before "ann".
+type Repository struct {
+	mu      sync.RWMutex
+	users   map[string]*entities.User
+	history []*entities.UserHistory
+	tracer  trace.Tracer
+}
+
+func NewRepository() (*Repository, error) {
+	return &Repository{
+		users:  make(map[string]*entities.User),
+		tracer: otel.Tracer("MemoryRepository")}, nil
+}
+
+// UnitOfWork runs the functions of a transaction directly. Each call takes the lock on its own, so the
+// calls of a unit of work may interleave with others and are not rolled back; every write still checks
+// existence and version under the lock.
+func (repo *Repository) UnitOfWork() domain.UnitOfWork {
+	return domain.NoopUnitOfWork{Tx: domain.Tx{
+		GetByID: repo.GetByID,
+		Save:    repo.Save,
+		Update:  repo.Update,
+		Remove:  repo.Remove,
+	}}
+}
+
+func (repo *Repository) Get(ctx context.Context, filter entities.UserFilter, page entities.PageRequest) (*entities.Page, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Get")
+	defer span.End()
+
+	if err := checkSort(page.Sort); err != nil {
+		return nil, err
+	}
+
+	if page.After != nil && !page.After.Matches(page.Sort) {
+		return nil, domainErrors.AppInvalidCursor
+	}
+
+	users := repo.list(filter, page.Sort)
+
+	if page.After != nil {
+		start, _ := slices.BinarySearchFunc(users, page.After, func(user *entities.User, cursor *entities.Cursor) int {
+			if compareToCursor(user, cursor, page.Sort) > 0 {
+				return 1
+			}
+			return -1
+		})
+		users = users[start:]
+	}
+
+	var next *entities.Cursor
+	if len(users) > page.Limit {
+		users = users[:page.Limit]
+		next = entities.NewCursor(users[len(users)-1], page.Sort)
+	}
+
+	span.SetAttributes(attribute.Int("repo.memory.rows.count", len(users)))
+
+	return &entities.Page{Users: users, Next: next}, nil
+}
+
+func (repo *Repository) Export(ctx context.Context, filter entities.UserFilter, sort []entities.SortKey, fn func(*entities.User) error) error {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Export")
+	defer span.End()
+
+	if err := checkSort(sort); err != nil {
+		return err
+	}
+
+	users := repo.list(filter, sort)
+	for _, user := range users {
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+
+	span.SetAttributes(attribute.Int("repo.memory.rows.count", len(users)))
+
+	return nil
+}
+
+func (repo *Repository) GetByID(ctx context.Context, ids []string) ([]*entities.User, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-GetByID")
+	defer span.End()
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	users := make([]*entities.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := repo.users[id]; ok && user.DeletedAt == nil {
+			users = append(users, clone(user))
+		}
+	}
+
+	span.SetAttributes(attribute.Int("repo.memory.rows.count", len(users)))
+
+	return users, nil
+}
+
+func (repo *Repository) Search(ctx context.Context, query string, limit int) ([]*entities.User, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Search")
+	defer span.End()
+
+	query = strings.ToLower(query)
+
+	users := repo.list(entities.UserFilter{}, entities.DefaultSort)
+	users = slices.DeleteFunc(users, func(user *entities.User) bool {
+		return !containsFold(&user.Name, query) && !containsFold(user.Email, query) && !containsFold(user.Location, query)
+	})
+
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("repo.memory.rows.count", len(users)))
+
+	return users, nil
+}
+
+func (repo *Repository) Save(ctx context.Context, user *entities.User) (*entities.User, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Save")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.checkNew(user, nil); err != nil {
+		return nil, err
+	}
+
+	return repo.insert(ctx, user, time.Now()), nil
+}
+
+// SaveBatch stores the valid users and reports the others, or stores nothing if the batch is atomic
+// and any item fails.
+func (repo *Repository) SaveBatch(ctx context.Context, users []*entities.User, atomic bool) ([]*entities.BatchResult, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-SaveBatch")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	results := make([]*entities.BatchResult, len(users))
+	accepted := make([]*entities.User, 0, len(users))
+	failed := false
+
+	for i, user := range users {
+		if err := repo.checkNew(user, accepted); err != nil {
+			results[i] = &entities.BatchResult{Err: err}
+			failed = true
+			continue
+		}
+		accepted = append(accepted, user)
+	}
+
+	if atomic && failed {
+		for i, result := range results {
+			if result == nil {
+				results[i] = &entities.BatchResult{Err: domainErrors.AppBatchAborted}
+			}
+		}
+		return results, nil
+	}
+
+	now := time.Now()
+	for i, user := range users {
+		if results[i] == nil {
+			results[i] = &entities.BatchResult{User: repo.insert(ctx, user, now)}
+		}
+	}
+
+	return results, nil
+}
+
+func (repo *Repository) Update(ctx context.Context, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Update")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	before, err := repo.active(id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	after := clone(before)
+	applyFields(after, fields)
+
+	if err = repo.checkEmail(after, nil); err != nil {
+		return nil, err
+	}
+
+	return repo.replace(ctx, entities.HistoryUpdated, before, after, time.Now()), nil
+}
+
+func (repo *Repository) UpdateBatch(ctx context.Context, ids []string, fields map[string]interface{}) (*entities.BatchChange, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-UpdateBatch")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	changed := make([]*entities.User, 0, len(ids))
+	for _, id := range ids {
+		if before, err := repo.active(id, entities.AnyVersion); err == nil {
+			after := clone(before)
+			applyFields(after, fields)
+			changed = append(changed, after)
+		}
+	}
+
+	for i, user := range changed {
+		if err := repo.checkEmail(user, changed[:i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo.changeBatch(ctx, ids, entities.HistoryUpdated, changed), nil
+}
+
+func (repo *Repository) Remove(ctx context.Context, id string, version int64) error {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Remove")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	before, err := repo.active(id, version)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	after := clone(before)
+	after.DeletedAt = &now
+
+	repo.replace(ctx, entities.HistoryDeleted, before, after, now)
+
+	return nil
+}
+
+func (repo *Repository) RemoveBatch(ctx context.Context, ids []string) (*entities.BatchChange, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-RemoveBatch")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+
+	changed := make([]*entities.User, 0, len(ids))
+	for _, id := range ids {
+		if before, err := repo.active(id, entities.AnyVersion); err == nil {
+			after := clone(before)
+			after.DeletedAt = &now
+			changed = append(changed, after)
+		}
+	}
+
+	return repo.changeBatch(ctx, ids, entities.HistoryDeleted, changed), nil
+}
+
+func (repo *Repository) GetDeleted(ctx context.Context) ([]*entities.User, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-GetDeleted")
+	defer span.End()
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	users := make([]*entities.User, 0)
+	for _, user := range repo.users {
+		if user.DeletedAt != nil {
+			users = append(users, clone(user))
+		}
+	}
+
+	slices.SortFunc(users, func(a *entities.User, b *entities.User) int {
+		if result := b.DeletedAt.Compare(*a.DeletedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	span.SetAttributes(attribute.Int("repo.memory.rows.count", len(users)))
+
+	return users, nil
+}
+
+func (repo *Repository) Restore(ctx context.Context, id string) (*entities.User, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Restore")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	before, ok := repo.users[id]
+	if !ok || before.DeletedAt == nil {
+		return nil, domainErrors.AppUserNotFound
+	}
+
+	after := clone(before)
+	after.DeletedAt = nil
+
+	return repo.replace(ctx, entities.HistoryRestored, before, after, time.Now()), nil
+}
+
+func (repo *Repository) Purge(ctx context.Context, id string) error {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-Purge")
+	defer span.End()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user, ok := repo.users[id]
+	if !ok {
+		return domainErrors.AppUserNotFound
+	}
+
+	if user.DeletedAt == nil {
+		return domainErrors.AppUserNotDeleted
+	}
+
+	delete(repo.users, id)
+
+	return nil
+}
+
+func (repo *Repository) GetHistory(ctx context.Context, id string) ([]*entities.UserHistory, error) {
+	_, span := repo.tracer.Start(ctx, "MemoryRepository-GetHistory")
+	defer span.End()
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	// Users in the trash still exist, so their history is listed; purged users no longer do.
+	if _, ok := repo.users[id]; !ok {
+		return nil, domainErrors.AppUserNotFound
+	}
+
+	history := make([]*entities.UserHistory, 0)
+	for _, entry := range repo.history {
+		if entry.UserID == id {
+			copied := *entry
+			copied.Before = clone(entry.Before)
+			copied.After = clone(entry.After)
+			history = append(history, &copied)
+		}
+	}
+
+	span.SetAttributes(attribute.Int("repo.memory.rows.count", len(history)))
+
+	return history, nil
+}
+
+// list returns copies of the users that match the filter, sorted.
+func (repo *Repository) list(filter entities.UserFilter, sort []entities.SortKey) []*entities.User {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	users := make([]*entities.User, 0)
+	for _, user := range repo.users {
+		if matches(user, filter) {
+			users = append(users, clone(user))
+		}
+	}
+
+	sortUsers(users, sort)
+
+	return users
+}
+
+// active returns the stored user if it is not in the trash and has the expected version.
+func (repo *Repository) active(id string, version int64) (*entities.User, error) {
+	user, ok := repo.users[id]
+	if !ok || user.DeletedAt != nil {
+		return nil, domainErrors.AppUserNotFound
+	}
+
+	if version != entities.AnyVersion && user.Version != version {
+		return nil, domainErrors.AppVersionMismatch
+	}
+
+	return user, nil
+}
+
+// checkNew tells whether the user can be inserted next to the stored users and the pending ones.
+func (repo *Repository) checkNew(user *entities.User, pending []*entities.User) error {
+	if _, ok := repo.users[user.ID]; ok {
+		return domainErrors.AppUserExists
+	}
+
+	for _, other := range pending {
+		if other.ID == user.ID {
+			return domainErrors.AppUserExists
+		}
+	}
+
+	return repo.checkEmail(user, pending)
+}
+
+// checkEmail tells whether the email of the user is free among the stored users, including the ones in
+// the trash, and the pending ones.
+func (repo *Repository) checkEmail(user *entities.User, pending []*entities.User) error {
+	if user.Email == nil {
+		return nil
+	}
+
+	email := entities.NormalizeEmail(*user.Email)
+
+	taken := func(other *entities.User) bool {
+		return other.ID != user.ID && other.Email != nil && entities.NormalizeEmail(*other.Email) == email
+	}
+
+	for _, other := range repo.users {
+		if taken(other) {
+			return domainErrors.AppEmailTaken
+		}
+	}
+
+	if slices.ContainsFunc(pending, taken) {
+		return domainErrors.AppEmailTaken
+	}
+
+	return nil
+}
+
+func (repo *Repository) insert(ctx context.Context, user *entities.User, now time.Time) *entities.User {
+	stored := clone(user)
+	stored.Email = normalizeEmail(stored.Email)
+	stored.CreatedAt = timestamp(now)
+	stored.UpdatedAt = stored.CreatedAt
+	stored.Version = 1
+	stored.DeletedAt = nil
+
+	repo.users[stored.ID] = stored
+	repo.recordHistory(ctx, entities.HistoryCreated, nil, stored, now)
+
+	return clone(stored)
+}
+
+// replace stores the new state of a user, advancing updated_at and the version as the triggers of the
+// users table do.
+func (repo *Repository) replace(ctx context.Context, action string, before *entities.User, after *entities.User, now time.Time) *entities.User {
+	after.Email = normalizeEmail(after.Email)
+	after.UpdatedAt = timestamp(now)
+	after.Version = before.Version + 1
+	if after.DeletedAt != nil {
+		deletedAt := timestamp(*after.DeletedAt)
+		after.DeletedAt = &deletedAt
+	}
+
+	repo.users[after.ID] = after
+	repo.recordHistory(ctx, action, before, after, now)
+
+	return clone(after)
+}
+
+func (repo *Repository) changeBatch(ctx context.Context, ids []string, action string, changed []*entities.User) *entities.BatchChange {
+	result := &entities.BatchChange{Users: []*entities.User{}, NotFound: []string{}}
+
+	now := time.Now()
+	after := make(map[string]*entities.User, len(changed))
+	for _, user := range changed {
+		after[user.ID] = repo.replace(ctx, action, repo.users[user.ID], user, now)
+	}
+
+	for _, id := range ids {
+		if user, ok := after[id]; ok {
+			result.Users = append(result.Users, user)
+		} else {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+
+	return result
+}
+
+func (repo *Repository) recordHistory(ctx context.Context, action string, before *entities.User, after *entities.User, now time.Time) {
+	entry := &entities.UserHistory{
+		ID:            int64(len(repo.history) + 1),
+		Action:        action,
+		Before:        clone(before),
+		After:         clone(after),
+		ChangedFields: entities.ChangedFields(before, after),
+		CreatedAt:     timestamp(now),
+	}
+
+	if before != nil {
+		entry.UserID = before.ID
+	} else {
+		entry.UserID = after.ID
+	}
+
+	if actor := domain.ActorFromContext(ctx); actor != "" {
+		entry.Actor = &actor
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		traceID := spanContext.TraceID().String()
+		entry.TraceID = &traceID
+	}
+
+	repo.history = append(repo.history, entry)
+}
+
+// applyFields sets the fields of a partial update, with the same values the postgres repository accepts.
+func applyFields(user *entities.User, fields map[string]interface{}) {
+	if value, ok := fields["name"]; ok {
+		user.Name = value.(string)
+	}
+
+	if value, ok := fields["birth"]; ok {
+		user.Birth = nil
+		if value != nil {
+			birth := value.(time.Time)
+			user.Birth = &birth
+		}
+	}
+
+	if value, ok := fields["email"]; ok {
+		user.Email = nil
+		if value != nil {
+			email := value.(string)
+			user.Email = &email
+		}
+	}
+
+	if value, ok := fields["location"]; ok {
+		user.Location = nil
+		if value != nil {
+			location := value.(string)
+			user.Location = &location
+		}
+	}
+
+	if value, ok := fields["active"]; ok {
+		user.Active = value.(bool)
+	}
+}
+
+// clone copies the user and the values its fields point to, so that callers never share memory
+// with the store.
+func clone(user *entities.User) *entities.User {
+	if user == nil {
+		return nil
+	}
+
+	copied := *user
+	copied.Birth = clonePointer(user.Birth)
+	copied.Email = clonePointer(user.Email)
+	copied.Location = clonePointer(user.Location)
+	copied.DeletedAt = clonePointer(user.DeletedAt)
+
+	return &copied
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func normalizeEmail(email *string) *string {
+	if email == nil {
+		return nil
+	}
+	normalized := entities.NormalizeEmail(*email)
+	return &normalized
+}
+
+func containsFold(value *string, query string) bool {
+	return value != nil && strings.Contains(strings.ToLower(*value), query)
+}
+
+// timestamp drops what a Postgres TIMESTAMP column cannot hold: the time zone and the nanoseconds.
+func timestamp(value time.Time) time.Time {
+	return value.UTC().Truncate(time.Microsecond)
+}
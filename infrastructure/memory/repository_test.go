@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"users/domain"
+	"users/domain/entities"
+	"users/infrastructure/storagetest"
+)
+
+func TestRepository(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Repository {
+		repo, err := NewRepository()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestUnitOfWork(t *testing.T) {
+	storagetest.RunUnitOfWork(t, func(t *testing.T) (storagetest.Repository, domain.UnitOfWork) {
+		repo, err := NewRepository()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo, repo.UnitOfWork()
+	})
+}
+
+func TestRepositorySortsByteByByte(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewRepository()
+	require.NoError(t, err)
+
+	for _, name := range []string{"ann", "Zoe", "bob"} {
+		_, err = repo.Save(ctx, &entities.User{ID: uuid.New().String(), Name: name, Active: true})
+		require.NoError(t, err)
+	}
+
+	page, err := repo.Get(ctx, entities.UserFilter{}, entities.PageRequest{Limit: 10, Sort: entities.DefaultSort})
+	require.NoError(t, err)
+
+	names := make([]string, len(page.Users))
+	for i, user := range page.Users {
+		names[i] = user.Name
+	}
+	assert.Equal(t, []string{"Zoe", "ann", "bob"}, names)
+}
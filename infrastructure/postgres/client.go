@@ -8,27 +8,52 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"sync"
+	"sync/atomic"
+	"time"
+	"users/domain"
+	domainErrors "users/domain/errors"
 )
 
+// primaryPool names the pool of the primary in the spans.
+const primaryPool = "primary"
+
 type Client struct {
+	pool     *pgxpool.Pool
+	queries  *Queries
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	checks   sync.WaitGroup
+	dbName   string
+	uri      string
+	tracer   trace.Tracer
+}
+
+// replica is a read-only pool, used only while its last health check passed.
+type replica struct {
+	name    string
 	pool    *pgxpool.Pool
 	queries *Queries
-	dbName  string
-	uri     string
-	tracer  trace.Tracer
+	healthy atomic.Bool
 }
 
 func NewClient(config *Config) (*Client, error) {
-	uri := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", config.Username, config.Password, config.Host, config.Port, config.Database)
+	uri := config.connString()
 
 	connConfig, err := pgxpool.ParseConfig(uri)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse db config: %w", err)
 	}
+	connConfig.ConnConfig.ConnectTimeout = config.Timeout
+	config.Pool.apply(connConfig)
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 	defer cancel()
@@ -43,19 +68,121 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to ping db: %w", err)
 	}
 
-	return &Client{
+	client := &Client{
 		pool:    connPool,
 		queries: New(connPool),
-		dbName:  config.Database,
+		stop:    make(chan struct{}),
+		dbName:  connConfig.ConnConfig.Database,
 		uri:     uri,
 		tracer:  otel.Tracer("PostgresClient"),
-	}, nil
+	}
+
+	for i, dsn := range config.replicaConnStrings() {
+		replicaConfig, err := pgxpool.ParseConfig(dsn)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to parse replica config: %w", err)
+		}
+		replicaConfig.ConnConfig.ConnectTimeout = config.Timeout
+		config.Pool.apply(replicaConfig)
+
+		// The pool connects lazily, so an unreachable replica only starts unhealthy.
+		replicaPool, err := pgxpool.NewWithConfig(ctx, replicaConfig)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to create replica pool: %w", err)
+		}
+
+		r := &replica{name: fmt.Sprintf("replica-%d", i+1), pool: replicaPool, queries: New(replicaPool)}
+		r.healthy.Store(replicaPool.Ping(ctx) == nil)
+		client.replicas = append(client.replicas, r)
+	}
+
+	if len(client.replicas) > 0 {
+		client.checks.Add(1)
+		go client.checkReplicas(config.ReplicaCheckPeriod, config.Timeout)
+	}
+
+	return client, nil
 }
 
 func (c *Client) Close() {
+	close(c.stop)
+	c.checks.Wait()
+
+	for _, r := range c.replicas {
+		r.pool.Close()
+	}
 	c.pool.Close()
 }
 
+// checkReplicas pings every replica each period until the client is closed, so that a replica
+// that failed is used again once it is back.
+func (c *Client) checkReplicas(period time.Duration, timeout time.Duration) {
+	defer c.checks.Done()
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			for _, r := range c.replicas {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				r.healthy.Store(r.pool.Ping(ctx) == nil)
+				cancel()
+			}
+		}
+	}
+}
+
+// read runs fn against a healthy replica, taking turns between them. It runs fn against the primary
+// instead when there is no healthy replica, when ctx asks for the primary, or when the replica cannot
+// be reached, which also marks it unhealthy until its next check. The span of ctx records the pool used.
+func (c *Client) read(ctx context.Context, fn func(*pgxpool.Pool, *Queries) error) error {
+	span := trace.SpanFromContext(ctx)
+
+	if r := c.replica(ctx); r != nil {
+		err := fn(r.pool, r.queries)
+		if err == nil || !isConnectionError(err) || ctx.Err() != nil {
+			span.SetAttributes(attribute.String("repo.postgres.pool", r.name))
+			return toTimeoutError(err)
+		}
+
+		r.healthy.Store(false)
+		span.SetAttributes(attribute.String("repo.postgres.pool.failed", r.name))
+	}
+
+	span.SetAttributes(attribute.String("repo.postgres.pool", primaryPool))
+
+	return toTimeoutError(fn(c.pool, c.queries))
+}
+
+func (c *Client) replica(ctx context.Context) *replica {
+	if len(c.replicas) == 0 || domain.ReadsFromPrimary(ctx) {
+		return nil
+	}
+
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		r := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// isConnectionError tells whether err happened before the query reached the server,
+// so it can be run somewhere else.
+func isConnectionError(err error) bool {
+	var connectErr *pgconn.ConnectError
+	return errors.As(err, &connectErr) || pgconn.SafeToRetry(err)
+}
+
 func (c *Client) Migrate() error {
 	db, err := sql.Open("pgx", c.uri)
 	if err != nil {
@@ -88,3 +215,47 @@ func (c *Client) Migrate() error {
 
 	return nil
 }
+
+// inTx runs fn inside a transaction started with options, which is committed only if fn succeeds.
+func (c *Client) inTx(ctx context.Context, options pgx.TxOptions, fn func(*Queries) error) (err error) {
+	tx, err := c.pool.BeginTx(ctx, options)
+	if err != nil {
+		return toTimeoutError(err)
+	}
+	defer func() {
+		if err != nil {
+			err = toTimeoutError(errors.Join(err, ignoreClosed(tx.Rollback(ctx))))
+		}
+	}()
+
+	if err = fn(c.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// queryCanceled is the code of the error Postgres raises when statement_timeout cancels a query.
+const queryCanceled = "57014"
+
+// toTimeoutError reports the errors caused by a deadline, a network timeout or statement_timeout
+// as errors.AppTimeout.
+func toTimeoutError(err error) error {
+	if err == nil || errors.Is(err, domainErrors.AppTimeout) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) || (errors.As(err, &pgErr) && pgErr.Code == queryCanceled) {
+		return fmt.Errorf("%w: %w", domainErrors.AppTimeout, err)
+	}
+
+	return err
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, pgx.ErrTxClosed) {
+		return nil
+	}
+	return err
+}
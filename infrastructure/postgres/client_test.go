@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+	"users/domain"
+	domainErrors "users/domain/errors"
+)
+
+func TestClientRead(t *testing.T) {
+	refused := &pgconn.ConnectError{Config: &pgconn.Config{}}
+
+	tests := []struct {
+		name         string
+		primary      bool
+		healthy      []bool
+		replicaErr   error
+		expectedErr  error
+		expectedPool string
+		expectedRuns []string
+		unhealthy    bool
+	}{
+		{
+			name:         "on healthy replica",
+			healthy:      []bool{true},
+			expectedPool: "replica-1",
+			expectedRuns: []string{"replica-1"},
+		},
+		{
+			name:         "on unhealthy replicas",
+			healthy:      []bool{false, false},
+			expectedPool: primaryPool,
+			expectedRuns: []string{primaryPool},
+		},
+		{
+			name:         "on read from primary requested",
+			primary:      true,
+			healthy:      []bool{true},
+			expectedPool: primaryPool,
+			expectedRuns: []string{primaryPool},
+		},
+		{
+			name:         "on replica unreachable",
+			healthy:      []bool{true},
+			replicaErr:   refused,
+			expectedPool: primaryPool,
+			expectedRuns: []string{"replica-1", primaryPool},
+			unhealthy:    true,
+		},
+		{
+			name:         "on query error",
+			healthy:      []bool{true},
+			replicaErr:   errors.New("syntax error"),
+			expectedErr:  errors.New("syntax error"),
+			expectedPool: "replica-1",
+			expectedRuns: []string{"replica-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primary := &Queries{}
+			client := &Client{queries: primary}
+			names := map[*Queries]string{primary: primaryPool}
+			for i, healthy := range test.healthy {
+				r := &replica{name: fmt.Sprintf("replica-%d", i+1), queries: &Queries{}}
+				r.healthy.Store(healthy)
+				names[r.queries] = r.name
+				client.replicas = append(client.replicas, r)
+			}
+
+			recorder := tracetest.NewSpanRecorder()
+			ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "test")
+			if test.primary {
+				ctx = domain.WithPrimary(ctx)
+			}
+
+			var runs []string
+			err := client.read(ctx, func(_ *pgxpool.Pool, queries *Queries) error {
+				runs = append(runs, names[queries])
+				if queries != primary {
+					return test.replicaErr
+				}
+				return nil
+			})
+			span.End()
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedRuns, runs)
+			assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.String("repo.postgres.pool", test.expectedPool))
+			if test.unhealthy {
+				assert.False(t, client.replicas[0].healthy.Load())
+			}
+		})
+	}
+}
+
+func TestClientReadTakesTurns(t *testing.T) {
+	client := &Client{queries: &Queries{}}
+	for _, name := range []string{"replica-1", "replica-2"} {
+		r := &replica{name: name, queries: &Queries{}}
+		r.healthy.Store(true)
+		client.replicas = append(client.replicas, r)
+	}
+
+	var used []*Queries
+	for range 4 {
+		_ = client.read(context.Background(), func(_ *pgxpool.Pool, queries *Queries) error {
+			used = append(used, queries)
+			return nil
+		})
+	}
+
+	assert.Equal(t, []*Queries{client.replicas[1].queries, client.replicas[0].queries, client.replicas[1].queries, client.replicas[0].queries}, used)
+}
+
+func TestToTimeoutError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		timeout bool
+	}{
+		{name: "on deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), timeout: true},
+		{name: "on statement timeout", err: &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}, timeout: true},
+		{name: "on other error", err: &pgconn.PgError{Code: "23505"}, timeout: false},
+		{name: "on cancel", err: context.Canceled, timeout: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := toTimeoutError(test.err)
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.timeout, errors.Is(err, domainErrors.AppTimeout))
+		})
+	}
+
+	assert.NoError(t, toTimeoutError(nil))
+}
@@ -1,53 +1,323 @@
 package postgres
 
 import (
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"maps"
+	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 	"users/domain/errors"
 )
 
 type Config struct {
+	// DSN is a connection string, as a URL or as libpq key/value pairs. When set, it is used instead
+	// of the separate fields.
+	DSN      string
 	Host     string
 	Port     string
 	Database string
 	Username string
 	Password string
 	Timeout  time.Duration
+	// Replicas are the connection strings of the read replicas, which are checked every
+	// ReplicaCheckPeriod.
+	Replicas           []string
+	ReplicaCheckPeriod time.Duration
+	Pool               *PoolConfig
+}
+
+func checkFields(host string, port string, database string, username string, password string) error {
+	if host == "" {
+		return errors.PostgresMissingHost
+	}
+
+	if port == "" {
+		return errors.PostgresMissingPort
+	}
+
+	if database == "" {
+		return errors.PostgresMissingDB
+	}
+
+	if username == "" {
+		return errors.PostgresMissingUser
+	}
+
+	if password == "" {
+		return errors.PostgresMissingPwd
+	}
+
+	return nil
+}
+
+// connString returns the connection string of the primary with the options of the pool. The user and
+// password are escaped, so they may hold any character.
+func (c *Config) connString() string {
+	options := c.Pool.connectionOptions()
+
+	if c.DSN != "" {
+		return withOptions(c.DSN, options)
+	}
+
+	uri := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.Username, c.Password),
+		Host:     net.JoinHostPort(c.Host, c.Port),
+		Path:     "/" + c.Database,
+		RawQuery: options.Encode(),
+	}
+
+	return uri.String()
+}
+
+// replicaConnStrings returns the connection strings of the replicas with the options of the pool, so
+// that they use the same TLS settings as the primary.
+func (c *Config) replicaConnStrings() []string {
+	options := c.Pool.connectionOptions()
+
+	dsns := make([]string, len(c.Replicas))
+	for i, dsn := range c.Replicas {
+		dsns[i] = withOptions(dsn, options)
+	}
+
+	return dsns
+}
+
+// String describes the config without its secrets, so that it can be logged.
+func (c *Config) String() string {
+	if c.DSN != "" {
+		return fmt.Sprintf("dsn=%q replicas=%d", redact(c.DSN), len(c.Replicas))
+	}
+
+	return fmt.Sprintf("host=%q port=%q database=%q user=%q password=%q replicas=%d",
+		c.Host, c.Port, c.Database, c.Username, redacted, len(c.Replicas))
+}
+
+// withOptions adds the options to a connection string in either of its forms. Later key/value pairs
+// override earlier ones, like query parameters set on the URL.
+func withOptions(dsn string, options url.Values) string {
+	if len(options) == 0 {
+		return dsn
+	}
+
+	if isURL(dsn) {
+		uri, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+
+		query := uri.Query()
+		for key := range options {
+			query.Set(key, options.Get(key))
+		}
+		uri.RawQuery = query.Encode()
+
+		return uri.String()
+	}
+
+	pairs := []string{dsn}
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		pairs = append(pairs, fmt.Sprintf("%s='%s'", key, quoteReplacer.Replace(options.Get(key))))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+const redacted = "xxxxx"
+
+// quoteReplacer escapes a value of a key/value connection string to be put between single quotes.
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// passwordPair matches the password of a key/value connection string, quoted or not.
+var passwordPair = regexp.MustCompile(`password\s*=\s*('(\\.|[^'])*'|\S+)`)
+
+// redact hides the password of a connection string in either of its forms. A URL may hold it in its
+// user info or in a password query parameter.
+func redact(dsn string) string {
+	if isURL(dsn) {
+		uri, err := url.Parse(dsn)
+		if err != nil {
+			return redacted
+		}
+
+		if query := uri.Query(); query.Has("password") {
+			query.Set("password", redacted)
+			uri.RawQuery = query.Encode()
+		}
+
+		return uri.Redacted()
+	}
+
+	return passwordPair.ReplaceAllString(dsn, "password="+redacted)
+}
+
+func isURL(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// sslModes are the values libpq accepts for sslmode.
+var sslModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// PoolConfig tunes the connections of the primary and the replicas. Zero values keep the defaults of pgx
+// and of the server, and an empty SSLMode keeps the one of the connection string.
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	StatementTimeout  time.Duration
+	ApplicationName   string
+	SSLMode           string
+	SSLRootCert       string
+}
+
+func NewPoolConfig(
+	maxConns int,
+	minConns int,
+	maxConnLifetime time.Duration,
+	maxConnIdleTime time.Duration,
+	healthCheckPeriod time.Duration,
+	statementTimeout time.Duration,
+	applicationName string,
+	sslMode string,
+	sslRootCert string,
+) (*PoolConfig, error) {
+	if maxConns < 0 || minConns < 0 || maxConns > math.MaxInt32 || (maxConns > 0 && minConns > maxConns) {
+		return nil, errors.PostgresInvalidPool
+	}
+
+	if maxConnLifetime < 0 || maxConnIdleTime < 0 || healthCheckPeriod < 0 || statementTimeout < 0 {
+		return nil, errors.PostgresInvalidDuration
+	}
+
+	if sslMode != "" && !sslModes[sslMode] {
+		return nil, errors.PostgresInvalidSSLMode
+	}
+
+	return &PoolConfig{
+		MaxConns:          int32(maxConns),
+		MinConns:          int32(minConns),
+		MaxConnLifetime:   maxConnLifetime,
+		MaxConnIdleTime:   maxConnIdleTime,
+		HealthCheckPeriod: healthCheckPeriod,
+		StatementTimeout:  statementTimeout,
+		ApplicationName:   applicationName,
+		SSLMode:           sslMode,
+		SSLRootCert:       sslRootCert,
+	}, nil
+}
+
+// apply sets the pool options on a pool config parsed from a connection string.
+func (p *PoolConfig) apply(config *pgxpool.Config) {
+	if p.MaxConns > 0 {
+		config.MaxConns = p.MaxConns
+	}
+
+	if p.MinConns > 0 {
+		config.MinConns = p.MinConns
+	}
+
+	if p.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = p.MaxConnLifetime
+	}
+
+	if p.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = p.MaxConnIdleTime
+	}
+
+	if p.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = p.HealthCheckPeriod
+	}
+
+	if p.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(p.StatementTimeout.Milliseconds(), 10)
+	}
+
+	if p.ApplicationName != "" {
+		config.ConnConfig.RuntimeParams["application_name"] = p.ApplicationName
+	}
+}
+
+// connectionOptions are the options of a connection string that set up TLS and name the application,
+// so that every connection, including the one running the migrations, uses them.
+func (p *PoolConfig) connectionOptions() url.Values {
+	options := url.Values{}
+
+	if p.SSLMode != "" {
+		options.Set("sslmode", p.SSLMode)
+	}
+
+	if p.SSLRootCert != "" {
+		options.Set("sslrootcert", p.SSLRootCert)
+	}
+
+	if p.ApplicationName != "" {
+		options.Set("application_name", p.ApplicationName)
+	}
+
+	return options
 }
 
 func NewConfig(
+	dsn string,
 	host string,
 	port string,
 	database string,
 	username string,
 	password string,
 	timeout time.Duration,
+	replicas []string,
+	replicaCheckPeriod time.Duration,
+	pool *PoolConfig,
 ) (*Config, error) {
-	if host == "" {
-		return nil, errors.PostgresMissingHost
-	}
-
-	if port == "" {
-		return nil, errors.PostgresMissingPort
+	if dsn != "" {
+		if _, err := pgconn.ParseConfig(dsn); err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.PostgresInvalidDSN, err)
+		}
+	} else if err := checkFields(host, port, database, username, password); err != nil {
+		return nil, err
 	}
 
-	if database == "" {
-		return nil, errors.PostgresMissingDB
+	for _, replica := range replicas {
+		if _, err := pgxpool.ParseConfig(replica); err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.PostgresInvalidReplica, err)
+		}
 	}
 
-	if username == "" {
-		return nil, errors.PostgresMissingUser
+	if len(replicas) > 0 && replicaCheckPeriod <= 0 {
+		return nil, errors.PostgresInvalidCheck
 	}
 
-	if password == "" {
-		return nil, errors.PostgresMissingPwd
+	if pool == nil {
+		pool = &PoolConfig{}
 	}
 
 	return &Config{
+		DSN:      dsn,
 		Host:     host,
 		Port:     port,
 		Database: database,
 		Username: username,
 		Password: password,
 		Timeout:  timeout,
+
+		Replicas:           replicas,
+		ReplicaCheckPeriod: replicaCheckPeriod,
+		Pool:               pool,
 	}, nil
 }
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"users/domain/entities"
+	"users/domain/errors"
+)
+
+// userColumns are scanned into User by name, through the db tags sqlc emits.
+const userColumns = "id, name, birth, email, location, created_at, updated_at, active, version, deleted_at"
+
+type sortColumn struct {
+	expression string
+	kind       string
+}
+
+// Nullable columns are compared through COALESCE so that keyset conditions
+// never meet a NULL; the defaults match entities.User.SortValue.
+var sortColumns = map[entities.SortField]sortColumn{
+	entities.SortByName:      {expression: "name", kind: "text"},
+	entities.SortByCreatedAt: {expression: "COALESCE(created_at, '0001-01-01'::timestamp)", kind: "timestamp"},
+	entities.SortByUpdatedAt: {expression: "COALESCE(updated_at, '0001-01-01'::timestamp)", kind: "timestamp"},
+	entities.SortByBirth:     {expression: "COALESCE(birth, '0001-01-01'::date)", kind: "date"},
+	entities.SortByEmail:     {expression: "COALESCE(email, '')", kind: "text"},
+}
+
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+func (b *queryBuilder) filter(filter entities.UserFilter) {
+	b.where("deleted_at IS NULL")
+
+	if filter.Active != nil {
+		b.where("active = " + b.arg(*filter.Active))
+	}
+
+	if filter.Location != nil {
+		b.where("lower(location) = lower(" + b.arg(*filter.Location) + ")")
+	}
+
+	if filter.EmailDomain != nil {
+		b.where("lower(split_part(email, '@', 2)) = lower(" + b.arg(*filter.EmailDomain) + ")")
+	}
+
+	b.timeRange("created_at", "timestamp", filter.CreatedAt)
+	b.timeRange("updated_at", "timestamp", filter.UpdatedAt)
+	b.timeRange("birth", "date", filter.Birth)
+}
+
+func (b *queryBuilder) timeRange(column string, kind string, value entities.TimeRange) {
+	if value.From != nil {
+		b.where(fmt.Sprintf("%s >= %s::%s", column, b.arg(*value.From), kind))
+	}
+
+	if value.To != nil {
+		b.where(fmt.Sprintf("%s <= %s::%s", column, b.arg(*value.To), kind))
+	}
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+func (b *queryBuilder) after(sort []entities.SortKey, cursor *entities.Cursor) {
+	// Expands the row comparison by hand because sort keys may have different directions:
+	// (k1 > v1) OR (k1 = v1 AND k2 < v2) OR ... OR (k1 = v1 AND ... AND id > last_id).
+	var alternatives []string
+	var equalities []string
+
+	for i, key := range sort {
+		column := sortColumns[key.Field]
+		value := fmt.Sprintf("%s::%s", b.arg(cursor.Values[i]), column.kind)
+
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+
+		comparison := fmt.Sprintf("%s %s %s", column.expression, operator, value)
+		alternatives = append(alternatives, strings.Join(append(equalities, comparison), " AND "))
+		equalities = append(equalities, fmt.Sprintf("%s = %s", column.expression, value))
+	}
+
+	comparison := "id > " + b.arg(cursor.ID) + "::text"
+	alternatives = append(alternatives, strings.Join(append(equalities, comparison), " AND "))
+
+	b.where("((" + strings.Join(alternatives, ") OR (") + "))")
+}
+
+func orderBy(sort []entities.SortKey) string {
+	terms := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+		terms = append(terms, sortColumns[key.Field].expression+" "+direction)
+	}
+	terms = append(terms, "id ASC")
+	return strings.Join(terms, ", ")
+}
+
+func checkSort(sort []entities.SortKey) error {
+	for _, key := range sort {
+		if _, ok := sortColumns[key.Field]; !ok {
+			return fmt.Errorf("unknown sort field %q", key.Field)
+		}
+	}
+	return nil
+}
+
+func buildListUsers(filter entities.UserFilter, page entities.PageRequest) (string, []interface{}, error) {
+	if err := checkSort(page.Sort); err != nil {
+		return "", nil, err
+	}
+
+	var b queryBuilder
+
+	b.filter(filter)
+
+	if page.After != nil {
+		if !page.After.Matches(page.Sort) {
+			return "", nil, errors.AppInvalidCursor
+		}
+		b.after(page.Sort, page.After)
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	limit := b.arg(page.Limit + 1)
+
+	query := fmt.Sprintf("SELECT %s FROM users %s ORDER BY %s LIMIT %s", userColumns, b.whereClause(), orderBy(page.Sort), limit)
+
+	return query, b.args, nil
+}
+
+func buildExportUsers(filter entities.UserFilter, sort []entities.SortKey) (string, []interface{}, error) {
+	if err := checkSort(sort); err != nil {
+		return "", nil, err
+	}
+
+	var b queryBuilder
+
+	b.filter(filter)
+
+	query := fmt.Sprintf("SELECT %s FROM users %s ORDER BY %s", userColumns, b.whereClause(), orderBy(sort))
+
+	return query, b.args, nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type snapshot struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Birth     *time.Time `json:"birth"`
+	Email     *string    `json:"email"`
+	Location  *string    `json:"location"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Active    bool       `json:"active"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+func recordHistory(ctx context.Context, queries *Queries, action string, before *entities.User, after *entities.User) error {
+	arg, err := toCreateUserHistoryParams(ctx, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	return queries.CreateUserHistory(ctx, arg)
+}
+
+// recordHistories writes several history entries in a single round trip.
+func recordHistories(ctx context.Context, queries *Queries, args []CreateUserHistoriesParams) error {
+	var err error
+	queries.CreateUserHistories(ctx, args).Exec(func(_ int, execErr error) {
+		if execErr != nil && err == nil {
+			err = execErr
+		}
+	})
+	return err
+}
+
+func toCreateUserHistoryParams(ctx context.Context, action string, before *entities.User, after *entities.User) (CreateUserHistoryParams, error) {
+	var arg CreateUserHistoryParams
+
+	if before != nil {
+		arg.UserID = before.ID
+	} else {
+		arg.UserID = after.ID
+	}
+
+	var err error
+
+	if arg.Before, err = toSnapshot(before); err != nil {
+		return CreateUserHistoryParams{}, err
+	}
+
+	if arg.After, err = toSnapshot(after); err != nil {
+		return CreateUserHistoryParams{}, err
+	}
+
+	arg.Action = action
+	arg.ChangedFields = entities.ChangedFields(before, after)
+
+	if actor := domain.ActorFromContext(ctx); actor != "" {
+		arg.Actor = pgtype.Text{String: actor, Valid: true}
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		arg.TraceID = pgtype.Text{String: spanContext.TraceID().String(), Valid: true}
+	}
+
+	return arg, nil
+}
+
+func toSnapshot(user *entities.User) ([]byte, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(snapshot{
+		ID:        user.ID,
+		Name:      user.Name,
+		Birth:     user.Birth,
+		Email:     user.Email,
+		Location:  user.Location,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Active:    user.Active,
+		Version:   user.Version,
+		DeletedAt: user.DeletedAt,
+	})
+}
+
+func fromSnapshot(data []byte) (*entities.User, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var value snapshot
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return &entities.User{
+		ID:        value.ID,
+		Name:      value.Name,
+		Birth:     value.Birth,
+		Email:     value.Email,
+		Location:  value.Location,
+		CreatedAt: value.CreatedAt,
+		UpdatedAt: value.UpdatedAt,
+		Active:    value.Active,
+		Version:   value.Version,
+		DeletedAt: value.DeletedAt,
+	}, nil
+}
+
+func toUserHistory(row UserHistory) (*entities.UserHistory, error) {
+	before, err := fromSnapshot(row.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := fromSnapshot(row.After)
+	if err != nil {
+		return nil, err
+	}
+
+	var actor *string
+	if row.Actor.Valid {
+		actor = &row.Actor.String
+	}
+
+	var traceID *string
+	if row.TraceID.Valid {
+		traceID = &row.TraceID.String
+	}
+
+	return &entities.UserHistory{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		Action:        row.Action,
+		Before:        before,
+		After:         after,
+		ChangedFields: row.ChangedFields,
+		Actor:         actor,
+		TraceID:       traceID,
+		CreatedAt:     row.CreatedAt.Time,
+	}, nil
+}
@@ -9,12 +9,26 @@ import (
 )
 
 type User struct {
-	ID        string
-	Name      string
-	Birth     pgtype.Date
-	Email     pgtype.Text
-	Location  pgtype.Text
-	CreatedAt pgtype.Timestamp
-	UpdatedAt pgtype.Timestamp
-	Active    bool
+	ID        string           `db:"id"`
+	Name      string           `db:"name"`
+	Birth     pgtype.Date      `db:"birth"`
+	Email     pgtype.Text      `db:"email"`
+	Location  pgtype.Text      `db:"location"`
+	CreatedAt pgtype.Timestamp `db:"created_at"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at"`
+	Active    bool             `db:"active"`
+	Version   int64            `db:"version"`
+	DeletedAt pgtype.Timestamp `db:"deleted_at"`
+}
+
+type UserHistory struct {
+	ID            int64            `db:"id"`
+	UserID        string           `db:"user_id"`
+	Action        string           `db:"action"`
+	Before        []byte           `db:"before"`
+	After         []byte           `db:"after"`
+	ChangedFields []string         `db:"changed_fields"`
+	Actor         pgtype.Text      `db:"actor"`
+	TraceID       pgtype.Text      `db:"trace_id"`
+	CreatedAt     pgtype.Timestamp `db:"created_at"`
 }
@@ -17,16 +17,16 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3, $4, $5, $6
 )
-RETURNING id, name, birth, email, location, created_at, updated_at, active
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
 `
 
 type CreateUserParams struct {
-	ID       string
-	Name     string
-	Birth    pgtype.Date
-	Email    pgtype.Text
-	Location pgtype.Text
-	Active   bool
+	ID       string      `db:"id"`
+	Name     string      `db:"name"`
+	Birth    pgtype.Date `db:"birth"`
+	Email    pgtype.Text `db:"email"`
+	Location pgtype.Text `db:"location"`
+	Active   bool        `db:"active"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -48,23 +48,142 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1
+const createUserHistory = `-- name: CreateUserHistory :exec
+INSERT INTO user_history (
+  user_id, action, before, after, changed_fields, actor, trace_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id string) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
+type CreateUserHistoryParams struct {
+	UserID        string      `db:"user_id"`
+	Action        string      `db:"action"`
+	Before        []byte      `db:"before"`
+	After         []byte      `db:"after"`
+	ChangedFields []string    `db:"changed_fields"`
+	Actor         pgtype.Text `db:"actor"`
+	TraceID       pgtype.Text `db:"trace_id"`
+}
+
+func (q *Queries) CreateUserHistory(ctx context.Context, arg CreateUserHistoryParams) error {
+	_, err := q.db.Exec(ctx, createUserHistory,
+		arg.UserID,
+		arg.Action,
+		arg.Before,
+		arg.After,
+		arg.ChangedFields,
+		arg.Actor,
+		arg.TraceID,
+	)
 	return err
 }
 
+const deleteUser = `-- name: DeleteUser :one
+UPDATE users
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND ($2::bigint = 0 OR version = $2)
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+type DeleteUserParams struct {
+	ID              string `db:"id"`
+	ExpectedVersion int64  `db:"expected_version"`
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, deleteUser, arg.ID, arg.ExpectedVersion)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteUsers = `-- name: DeleteUsers :many
+UPDATE users
+SET deleted_at = NOW()
+WHERE id = ANY(CAST($1::text[] AS bpchar[]))
+  AND deleted_at IS NULL
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+func (q *Queries) DeleteUsers(ctx context.Context, ids []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, deleteUsers, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findUserIDs = `-- name: FindUserIDs :many
+SELECT id FROM users
+WHERE id = ANY(CAST($1::text[] AS bpchar[]))
+`
+
+func (q *Queries) FindUserIDs(ctx context.Context, ids []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, findUserIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUser = `-- name: GetUser :one
-SELECT id, name, birth, email, location, created_at, updated_at, active FROM users
-WHERE id = $1 LIMIT 1
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
@@ -79,13 +198,15 @@ func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, name, birth, email, location, created_at, updated_at, active FROM users
-WHERE id = ANY($1::text[])
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id = ANY($1::text[]) AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUsers(ctx context.Context, dollar_1 []string) ([]User, error) {
@@ -106,6 +227,8 @@ func (q *Queries) GetUsers(ctx context.Context, dollar_1 []string) ([]User, erro
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -117,9 +240,22 @@ func (q *Queries) GetUsers(ctx context.Context, dollar_1 []string) ([]User, erro
 	return items, nil
 }
 
+const hardDeleteUser = `-- name: HardDeleteUser :execrows
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) HardDeleteUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, hardDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const listActiveUsers = `-- name: ListActiveUsers :many
-SELECT id, name, birth, email, location, created_at, updated_at, active FROM users
-WHERE active
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE active AND deleted_at IS NULL
 ORDER BY name
 `
 
@@ -141,6 +277,81 @@ func (q *Queries) ListActiveUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedUsers = `-- name: ListDeletedUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+`
+
+func (q *Queries) ListDeletedUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.Query(ctx, listDeletedUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserHistory = `-- name: ListUserHistory :many
+SELECT id, user_id, action, before, after, changed_fields, actor, trace_id, created_at FROM user_history
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListUserHistory(ctx context.Context, userID string) ([]UserHistory, error) {
+	rows, err := q.db.Query(ctx, listUserHistory, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserHistory
+	for rows.Next() {
+		var i UserHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Action,
+			&i.Before,
+			&i.After,
+			&i.ChangedFields,
+			&i.Actor,
+			&i.TraceID,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -153,7 +364,8 @@ func (q *Queries) ListActiveUsers(ctx context.Context) ([]User, error) {
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, birth, email, location, created_at, updated_at, active FROM users
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE deleted_at IS NULL
 ORDER BY name
 `
 
@@ -175,6 +387,146 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :one
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) LockUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, lockUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const lockUsers = `-- name: LockUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id = ANY(CAST($1::text[] AS bpchar[]))
+  AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockUsers(ctx context.Context, ids []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, lockUsers, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE deleted_at IS NULL
+  AND ($1::text <% name
+   OR $1::text <% email
+   OR $1::text <% location)
+ORDER BY GREATEST(
+    word_similarity($1::text, name),
+    word_similarity($1::text, COALESCE(email, '')),
+    word_similarity($1::text, COALESCE(location, ''))
+) DESC, name, id
+LIMIT $2
+`
+
+type SearchUsersParams struct {
+	Query      string `db:"query"`
+	MaxResults int32  `db:"max_results"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, searchUsers, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -204,21 +556,24 @@ SET
   active = CASE WHEN $10::boolean
   THEN $11 ELSE active END
 WHERE id = $1
-RETURNING id, name, birth, email, location, created_at, updated_at, active
+  AND deleted_at IS NULL
+  AND ($12::bigint = 0 OR version = $12)
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
 `
 
 type UpdateUserParams struct {
-	ID               string
-	NameDoUpdate     bool
-	Name             string
-	BirthDoUpdate    bool
-	Birth            pgtype.Date
-	EmailDoUpdate    bool
-	Email            pgtype.Text
-	LocationDoUpdate bool
-	Location         pgtype.Text
-	ActiveDoUpdate   bool
-	Active           bool
+	ID               string      `db:"id"`
+	NameDoUpdate     bool        `db:"name_do_update"`
+	Name             string      `db:"name"`
+	BirthDoUpdate    bool        `db:"birth_do_update"`
+	Birth            pgtype.Date `db:"birth"`
+	EmailDoUpdate    bool        `db:"email_do_update"`
+	Email            pgtype.Text `db:"email"`
+	LocationDoUpdate bool        `db:"location_do_update"`
+	Location         pgtype.Text `db:"location"`
+	ActiveDoUpdate   bool        `db:"active_do_update"`
+	Active           bool        `db:"active"`
+	ExpectedVersion  int64       `db:"expected_version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -234,6 +589,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Location,
 		arg.ActiveDoUpdate,
 		arg.Active,
+		arg.ExpectedVersion,
 	)
 	var i User
 	err := row.Scan(
@@ -245,6 +601,87 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const updateUsers = `-- name: UpdateUsers :many
+UPDATE users
+SET
+  name = CASE WHEN $1::boolean
+  THEN $2 ELSE name END,
+
+  birth = CASE WHEN $3::boolean
+  THEN $4 ELSE birth END,
+
+  email = CASE WHEN $5::boolean
+  THEN $6 ELSE email END,
+
+  location = CASE WHEN $7::boolean
+  THEN $8 ELSE location END,
+
+  active = CASE WHEN $9::boolean
+  THEN $10 ELSE active END
+WHERE id = ANY(CAST($11::text[] AS bpchar[]))
+  AND deleted_at IS NULL
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+type UpdateUsersParams struct {
+	NameDoUpdate     bool        `db:"name_do_update"`
+	Name             string      `db:"name"`
+	BirthDoUpdate    bool        `db:"birth_do_update"`
+	Birth            pgtype.Date `db:"birth"`
+	EmailDoUpdate    bool        `db:"email_do_update"`
+	Email            pgtype.Text `db:"email"`
+	LocationDoUpdate bool        `db:"location_do_update"`
+	Location         pgtype.Text `db:"location"`
+	ActiveDoUpdate   bool        `db:"active_do_update"`
+	Active           bool        `db:"active"`
+	Ids              []string    `db:"ids"`
+}
+
+func (q *Queries) UpdateUsers(ctx context.Context, arg UpdateUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, updateUsers,
+		arg.NameDoUpdate,
+		arg.Name,
+		arg.BirthDoUpdate,
+		arg.Birth,
+		arg.EmailDoUpdate,
+		arg.Email,
+		arg.LocationDoUpdate,
+		arg.Location,
+		arg.ActiveDoUpdate,
+		arg.Active,
+		arg.Ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
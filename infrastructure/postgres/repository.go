@@ -2,12 +2,23 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"time"
 	"users/domain/entities"
+	domainErrors "users/domain/errors"
+)
+
+const (
+	uniqueViolation = "23505"
+	primaryKey      = "users_pkey"
+	emailIndex      = "users_email_lower_idx"
 )
 
 type Repository struct {
@@ -23,25 +34,125 @@ func NewRepository(
 		tracer: otel.Tracer("PostgresRepository")}, nil
 }
 
-func (repo *Repository) Get(ctx context.Context) ([]*entities.User, error) {
+func (repo *Repository) Get(ctx context.Context, filter entities.UserFilter, page entities.PageRequest) (*entities.Page, error) {
 	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Get")
 	defer span.End()
 
-	rows, err := repo.client.queries.ListActiveUsers(tracerCtx)
+	query, args, err := buildListUsers(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []User
+	err = repo.client.read(tracerCtx, func(pool *pgxpool.Pool, _ *Queries) error {
+		result, err := pool.Query(tracerCtx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		rows, err = pgx.CollectRows(result, pgx.RowToStructByName[User])
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	span.SetAttributes(attribute.Int("repo.postgres.rows.count", len(rows)))
 
-	return toUserList(rows), nil
+	var next *entities.Cursor
+	if len(rows) > page.Limit {
+		rows = rows[:page.Limit]
+		next = entities.NewCursor(toUser(rows[len(rows)-1]), page.Sort)
+	}
+
+	return &entities.Page{Users: toUserList(rows), Next: next}, nil
+}
+
+// Export hands the users to fn one at a time while they are read from the connection,
+// so the result set is never held in memory.
+func (repo *Repository) Export(ctx context.Context, filter entities.UserFilter, sort []entities.SortKey, fn func(*entities.User) error) error {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Export")
+	defer span.End()
+
+	query, args, err := buildExportUsers(filter, sort)
+	if err != nil {
+		return err
+	}
+
+	// read only moves to the primary on errors raised before the query reached the server,
+	// so no user is handed to fn twice.
+	count := 0
+	err = repo.client.read(tracerCtx, func(pool *pgxpool.Pool, _ *Queries) (err error) {
+		tx, err := pool.BeginTx(tracerCtx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, ignoreClosed(tx.Rollback(tracerCtx)))
+		}()
+
+		// The export lasts as long as the client takes to download it, so DB_STATEMENT_TIMEOUT, meant for
+		// the other queries, does not apply; the request context still cancels it.
+		if _, err = tx.Exec(tracerCtx, "SET LOCAL statement_timeout = 0"); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(tracerCtx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			row, err := pgx.RowToStructByName[User](rows)
+			if err != nil {
+				return err
+			}
+
+			if err = fn(toUser(row)); err != nil {
+				return err
+			}
+			count++
+		}
+
+		return rows.Err()
+	})
+
+	span.SetAttributes(attribute.Int("repo.postgres.rows.count", count))
+
+	return err
 }
 
 func (repo *Repository) GetByID(ctx context.Context, ids []string) ([]*entities.User, error) {
 	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-GetByID")
 	defer span.End()
 
-	rows, err := repo.client.queries.GetUsers(tracerCtx, ids)
+	var users []*entities.User
+	err := repo.client.read(tracerCtx, func(_ *pgxpool.Pool, queries *Queries) (err error) {
+		users, err = txGetByID(tracerCtx, queries, ids)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("repo.postgres.rows.count", len(users)))
+
+	return users, nil
+}
+
+func (repo *Repository) Search(ctx context.Context, query string, limit int) ([]*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Search")
+	defer span.End()
+
+	var rows []User
+	err := repo.client.read(tracerCtx, func(_ *pgxpool.Pool, queries *Queries) (err error) {
+		rows, err = queries.SearchUsers(tracerCtx, SearchUsersParams{
+			Query:      query,
+			MaxResults: int32(limit),
+		})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -55,41 +166,497 @@ func (repo *Repository) Save(ctx context.Context, user *entities.User) (*entitie
 	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Save")
 	defer span.End()
 
-	arg, err := toSaveUserParams(user)
+	var result *entities.User
+	err := repo.inTx(tracerCtx, func(queries *Queries) (err error) {
+		result, err = txSave(tracerCtx, queries, user)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	row, err := repo.client.queries.CreateUser(tracerCtx, arg)
+	return result, nil
+}
+
+// SaveBatch sends all the inserts in a single round trip and a single transaction. An atomic batch is
+// rolled back at the first failing insert. Otherwise the inserts that conflict with another user are
+// skipped and reported, so the batch runs once however many items fail.
+func (repo *Repository) SaveBatch(ctx context.Context, users []*entities.User, atomic bool) ([]*entities.BatchResult, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-SaveBatch")
+	defer span.End()
+
+	params := make([]CreateUsersParams, len(users))
+	for i, user := range users {
+		arg, err := toSaveUserParams(user)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = CreateUsersParams(arg)
+	}
+
+	results := make([]*entities.BatchResult, len(users))
+	failed := -1
+
+	err := repo.inTx(tracerCtx, func(queries *Queries) error {
+		var created []*entities.User
+		var err error
+
+		if atomic {
+			created, failed, err = txCreateUsers(tracerCtx, queries, params)
+		} else {
+			created, err = txCreateUsersSkippingConflicts(tracerCtx, queries, params, results)
+		}
+		if err != nil {
+			return err
+		}
+
+		history := make([]CreateUserHistoriesParams, 0, len(created))
+		for i, user := range created {
+			if user == nil {
+				continue
+			}
+
+			arg, err := toCreateUserHistoryParams(tracerCtx, entities.HistoryCreated, nil, user)
+			if err != nil {
+				return err
+			}
+			history = append(history, CreateUserHistoriesParams(arg))
+
+			results[i] = &entities.BatchResult{User: user}
+		}
+
+		span.SetAttributes(attribute.Int("repo.postgres.batch.created", len(history)))
+
+		return recordHistories(tracerCtx, queries, history)
+	})
+
+	if failed >= 0 {
+		results[failed] = &entities.BatchResult{Err: err}
+		for i := range results {
+			if i != failed {
+				results[i] = &entities.BatchResult{Err: domainErrors.AppBatchAborted}
+			}
+		}
+		return results, nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	return toUser(row), nil
+	return results, nil
+}
+
+// txCreateUsers inserts every user, stopping at the first failing insert, whose index it returns.
+func txCreateUsers(ctx context.Context, queries *Queries, params []CreateUsersParams) ([]*entities.User, int, error) {
+	created := make([]*entities.User, len(params))
+	failed, failure := -1, error(nil)
+
+	queries.CreateUsers(ctx, params).QueryRow(func(i int, row User, err error) {
+		if err != nil {
+			if failed < 0 {
+				failed, failure = i, toDomainError(err)
+			}
+			return
+		}
+		created[i] = toUser(row)
+	})
+
+	return created, failed, failure
 }
 
-func (repo *Repository) Update(ctx context.Context, id string, fields map[string]interface{}) (*entities.User, error) {
+// txCreateUsersSkippingConflicts inserts the users that conflict with no other one and sets the result
+// of the others, which are left nil in the returned slice.
+func txCreateUsersSkippingConflicts(ctx context.Context, queries *Queries, params []CreateUsersParams, results []*entities.BatchResult) ([]*entities.User, error) {
+	batch := make([]CreateUsersSkippingConflictsParams, len(params))
+	for i, arg := range params {
+		batch[i] = CreateUsersSkippingConflictsParams(arg)
+	}
+
+	created := make([]*entities.User, len(params))
+	skipped := make([]string, 0)
+	var failure error
+
+	queries.CreateUsersSkippingConflicts(ctx, batch).QueryRow(func(i int, row User, err error) {
+		switch {
+		case err == nil:
+			created[i] = toUser(row)
+		case errors.Is(err, pgx.ErrNoRows):
+			skipped = append(skipped, params[i].ID)
+		case failure == nil:
+			failure = err
+		}
+	})
+	if failure != nil || len(skipped) == 0 {
+		return created, failure
+	}
+
+	// A skipped user whose ID exists, possibly inserted earlier in the batch, conflicts on its ID;
+	// otherwise only its email can conflict.
+	ids, err := queries.FindUserIDs(ctx, skipped)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		existing[id] = true
+	}
+
+	for i, user := range created {
+		if user != nil {
+			continue
+		}
+
+		if existing[params[i].ID] {
+			results[i] = &entities.BatchResult{Err: domainErrors.AppUserExists}
+		} else {
+			results[i] = &entities.BatchResult{Err: domainErrors.AppEmailTaken}
+		}
+	}
+
+	return created, nil
+}
+
+func (repo *Repository) Update(ctx context.Context, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
 	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Update")
 	defer span.End()
 
-	arg, err := toUpdateUserParams(id, fields)
+	var result *entities.User
+	err := repo.inTx(tracerCtx, func(queries *Queries) (err error) {
+		result, err = txUpdate(tracerCtx, queries, id, fields, version)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	row, err := repo.client.queries.UpdateUser(tracerCtx, arg)
+	return result, nil
+}
+
+func (repo *Repository) UpdateBatch(ctx context.Context, ids []string, fields map[string]interface{}) (*entities.BatchChange, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-UpdateBatch")
+	defer span.End()
+
+	arg, err := toUpdateUserParams("", fields)
 	if err != nil {
 		return nil, err
 	}
 
-	return toUser(row), nil
+	return repo.changeBatch(tracerCtx, ids, entities.HistoryUpdated, func(queries *Queries, ids []string) ([]User, error) {
+		rows, err := queries.UpdateUsers(tracerCtx, UpdateUsersParams{
+			NameDoUpdate:     arg.NameDoUpdate,
+			Name:             arg.Name,
+			BirthDoUpdate:    arg.BirthDoUpdate,
+			Birth:            arg.Birth,
+			EmailDoUpdate:    arg.EmailDoUpdate,
+			Email:            arg.Email,
+			LocationDoUpdate: arg.LocationDoUpdate,
+			Location:         arg.Location,
+			ActiveDoUpdate:   arg.ActiveDoUpdate,
+			Active:           arg.Active,
+			Ids:              ids,
+		})
+		return rows, toDomainError(err)
+	})
 }
 
-func (repo *Repository) Remove(ctx context.Context, id string) error {
+func (repo *Repository) Remove(ctx context.Context, id string, version int64) error {
 	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Remove")
 	defer span.End()
 
-	return repo.client.queries.DeleteUser(tracerCtx, id)
+	return repo.inTx(tracerCtx, func(queries *Queries) error {
+		return txRemove(tracerCtx, queries, id, version)
+	})
+}
+
+func (repo *Repository) RemoveBatch(ctx context.Context, ids []string) (*entities.BatchChange, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-RemoveBatch")
+	defer span.End()
+
+	return repo.changeBatch(tracerCtx, ids, entities.HistoryDeleted, func(queries *Queries, ids []string) ([]User, error) {
+		return queries.DeleteUsers(tracerCtx, ids)
+	})
+}
+
+// changeBatch locks the users that are not in the trash, applies change to them and records their history,
+// all in one transaction. The changed users are returned in the order of ids.
+func (repo *Repository) changeBatch(ctx context.Context, ids []string, action string, change func(*Queries, []string) ([]User, error)) (*entities.BatchChange, error) {
+	result := &entities.BatchChange{Users: []*entities.User{}, NotFound: []string{}}
+
+	err := repo.inTx(ctx, func(queries *Queries) error {
+		rows, err := queries.LockUsers(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		before := make(map[string]*entities.User, len(rows))
+		found := make([]string, 0, len(rows))
+		for _, row := range rows {
+			user := toUser(row)
+			before[user.ID] = user
+			found = append(found, user.ID)
+		}
+
+		if len(found) == 0 {
+			result.NotFound = ids
+			return nil
+		}
+
+		changed, err := change(queries, found)
+		if err != nil {
+			return err
+		}
+
+		after := make(map[string]*entities.User, len(changed))
+		history := make([]CreateUserHistoriesParams, 0, len(changed))
+		for _, row := range changed {
+			user := toUser(row)
+			after[user.ID] = user
+
+			arg, err := toCreateUserHistoryParams(ctx, action, before[user.ID], user)
+			if err != nil {
+				return err
+			}
+			history = append(history, CreateUserHistoriesParams(arg))
+		}
+
+		if err = recordHistories(ctx, queries, history); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if user, ok := after[id]; ok {
+				result.Users = append(result.Users, user)
+			} else {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetDeleted(ctx context.Context) ([]*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-GetDeleted")
+	defer span.End()
+
+	rows, err := repo.client.queries.ListDeletedUsers(tracerCtx)
+	if err != nil {
+		return nil, toTimeoutError(err)
+	}
+
+	span.SetAttributes(attribute.Int("repo.postgres.rows.count", len(rows)))
+
+	return toUserList(rows), nil
+}
+
+func (repo *Repository) Restore(ctx context.Context, id string) (*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Restore")
+	defer span.End()
+
+	var result *entities.User
+	err := repo.inTx(tracerCtx, func(queries *Queries) error {
+		row, err := queries.LockUser(tracerCtx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErrors.AppUserNotFound
+			}
+			return err
+		}
+
+		if !row.DeletedAt.Valid {
+			return domainErrors.AppUserNotFound
+		}
+
+		restored, err := queries.RestoreUser(tracerCtx, id)
+		if err != nil {
+			return err
+		}
+
+		result = toUser(restored)
+
+		return recordHistory(tracerCtx, queries, entities.HistoryRestored, toUser(row), result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) Purge(ctx context.Context, id string) error {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-Purge")
+	defer span.End()
+
+	return repo.inTx(tracerCtx, func(queries *Queries) error {
+		row, err := queries.LockUser(tracerCtx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErrors.AppUserNotFound
+			}
+			return err
+		}
+
+		if !row.DeletedAt.Valid {
+			return domainErrors.AppUserNotDeleted
+		}
+
+		_, err = queries.HardDeleteUser(tracerCtx, id)
+		return err
+	})
+}
+
+func (repo *Repository) GetHistory(ctx context.Context, id string) ([]*entities.UserHistory, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "PostgresRepository-GetHistory")
+	defer span.End()
+
+	// Users in the trash still exist, so their history is listed; purged users no longer do.
+	ids, err := repo.client.queries.FindUserIDs(tracerCtx, []string{id})
+	if err != nil {
+		return nil, toTimeoutError(err)
+	}
+
+	if len(ids) == 0 {
+		return nil, domainErrors.AppUserNotFound
+	}
+
+	rows, err := repo.client.queries.ListUserHistory(tracerCtx, id)
+	if err != nil {
+		return nil, toTimeoutError(err)
+	}
+
+	span.SetAttributes(attribute.Int("repo.postgres.rows.count", len(rows)))
+
+	history := make([]*entities.UserHistory, len(rows))
+	for i, row := range rows {
+		if history[i], err = toUserHistory(row); err != nil {
+			return nil, err
+		}
+	}
+
+	return history, nil
+}
+
+func txGetByID(ctx context.Context, queries *Queries, ids []string) ([]*entities.User, error) {
+	rows, err := queries.GetUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return toUserList(rows), nil
+}
+
+func txSave(ctx context.Context, queries *Queries, user *entities.User) (*entities.User, error) {
+	arg, err := toSaveUserParams(user)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := queries.CreateUser(ctx, arg)
+	if err != nil {
+		return nil, toDomainError(err)
+	}
+
+	result := toUser(row)
+
+	if err = recordHistory(ctx, queries, entities.HistoryCreated, nil, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func txUpdate(ctx context.Context, queries *Queries, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
+	arg, err := toUpdateUserParams(id, fields)
+	if err != nil {
+		return nil, err
+	}
+	arg.ExpectedVersion = version
+
+	before, err := lockActiveUser(ctx, queries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := queries.UpdateUser(ctx, arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.AppVersionMismatch
+		}
+		return nil, toDomainError(err)
+	}
+
+	result := toUser(row)
+
+	if err = recordHistory(ctx, queries, entities.HistoryUpdated, before, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func txRemove(ctx context.Context, queries *Queries, id string, version int64) error {
+	before, err := lockActiveUser(ctx, queries, id)
+	if err != nil {
+		return err
+	}
+
+	row, err := queries.DeleteUser(ctx, DeleteUserParams{
+		ID:              id,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErrors.AppVersionMismatch
+		}
+		return err
+	}
+
+	return recordHistory(ctx, queries, entities.HistoryDeleted, before, toUser(row))
+}
+
+// inTx runs fn inside a transaction, which is committed only if fn succeeds.
+func (repo *Repository) inTx(ctx context.Context, fn func(*Queries) error) error {
+	return repo.client.inTx(ctx, pgx.TxOptions{}, fn)
+}
+
+// toDomainError maps the constraint violations the domain has an error for.
+func toDomainError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case primaryKey:
+		return domainErrors.AppUserExists
+	case emailIndex:
+		return domainErrors.AppEmailTaken
+	}
+
+	return err
+}
+
+// lockActiveUser reads a user that is not in the trash and holds its row lock until the transaction ends.
+func lockActiveUser(ctx context.Context, queries *Queries, id string) (*entities.User, error) {
+	row, err := queries.LockUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.AppUserNotFound
+		}
+		return nil, err
+	}
+
+	if row.DeletedAt.Valid {
+		return nil, domainErrors.AppUserNotFound
+	}
+
+	return toUser(row), nil
 }
 
 func toUserList(rows []User) []*entities.User {
@@ -118,6 +685,11 @@ func toUser(row User) *entities.User {
 		location = &row.Location.String
 	}
 
+	var deletedAt *time.Time
+	if row.DeletedAt.Valid {
+		deletedAt = &row.DeletedAt.Time
+	}
+
 	user.ID = row.ID
 	user.Name = row.Name
 	user.Birth = birth
@@ -126,6 +698,8 @@ func toUser(row User) *entities.User {
 	user.CreatedAt = row.CreatedAt.Time
 	user.UpdatedAt = row.UpdatedAt.Time
 	user.Active = row.Active
+	user.Version = row.Version
+	user.DeletedAt = deletedAt
 
 	return &user
 }
@@ -142,7 +716,7 @@ func toSaveUserParams(user *entities.User) (CreateUserParams, error) {
 
 	var email pgtype.Text
 	if user.Email != nil {
-		email.String = *user.Email
+		email.String = entities.NormalizeEmail(*user.Email)
 		email.Valid = true
 	} else {
 		email.Valid = false
@@ -193,7 +767,7 @@ func toUpdateUserParams(id string, fields map[string]interface{}) (UpdateUserPar
 	if value, ok := fields["email"]; ok {
 		row.EmailDoUpdate = true
 		if value != nil {
-			row.Email.String = value.(string)
+			row.Email.String = entities.NormalizeEmail(value.(string))
 			row.Email.Valid = true
 		} else {
 			row.Email.Valid = false
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"users/domain"
+	domainErrors "users/domain/errors"
+	"users/infrastructure/storagetest"
+)
+
+// TestRepository runs against the database of TEST_DATABASE_URL, which is emptied before every test.
+func TestRepository(t *testing.T) {
+	client := newTestClient(t)
+
+	storagetest.Run(t, func(t *testing.T) storagetest.Repository {
+		if _, err := client.pool.Exec(context.Background(), "TRUNCATE users, user_history"); err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := NewRepository(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestUnitOfWork(t *testing.T) {
+	client := newTestClient(t)
+
+	storagetest.RunUnitOfWork(t, func(t *testing.T) (storagetest.Repository, domain.UnitOfWork) {
+		if _, err := client.pool.Exec(context.Background(), "TRUNCATE users, user_history"); err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := NewRepository(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		unitOfWork, err := NewUnitOfWork(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo, unitOfWork
+	})
+}
+
+// TestUserColumns fails when the columns selected by the hand-built queries no longer fill every field of User.
+func TestUserColumns(t *testing.T) {
+	userType := reflect.TypeOf(User{})
+
+	tags := make([]string, userType.NumField())
+	for i := range tags {
+		tags[i] = userType.Field(i).Tag.Get("db")
+	}
+
+	assert.ElementsMatch(t, tags, strings.Split(userColumns, ", "))
+}
+
+func TestToDomainError(t *testing.T) {
+	other := errors.New("an error occurred")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "on duplicated ID", err: &pgconn.PgError{Code: uniqueViolation, ConstraintName: primaryKey}, want: domainErrors.AppUserExists},
+		{name: "on duplicated email", err: &pgconn.PgError{Code: uniqueViolation, ConstraintName: emailIndex}, want: domainErrors.AppEmailTaken},
+		{name: "on other constraint", err: &pgconn.PgError{Code: uniqueViolation, ConstraintName: "users_other_key"}, want: &pgconn.PgError{Code: uniqueViolation, ConstraintName: "users_other_key"}},
+		{name: "on other error", err: other, want: other},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, toDomainError(test.err))
+		})
+	}
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	value := os.Getenv("TEST_DATABASE_URL")
+	if value == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	config, err := NewConfig(value, "", "", "", "", "", 5*time.Second, nil, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+
+	// The migrations are read relative to the root of the module.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if err = client.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
@@ -11,6 +11,8 @@ type Config struct {
 	IdleTimeout  time.Duration
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// AdminKey guards the /admin routes, which are disabled when it is empty.
+	AdminKey string
 }
 
 func NewConfig(
@@ -19,6 +21,7 @@ func NewConfig(
 	idleTimeout time.Duration,
 	readTimeout time.Duration,
 	writeTimeout time.Duration,
+	adminKey string,
 ) (*Config, error) {
 	if port < 0 || port > 65535 {
 		return nil, errors.ServerInvalidPort
@@ -34,5 +37,6 @@ func NewConfig(
 		IdleTimeout:  idleTimeout,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
+		AdminKey:     adminKey,
 	}, nil
 }
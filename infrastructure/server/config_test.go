@@ -16,7 +16,7 @@ func TestNewConfig(t *testing.T) {
 		port := 65536
 		prefix := "/api"
 
-		_, err := NewConfig(port, prefix, idleTimeout, readTimeout, writeTimeout)
+		_, err := NewConfig(port, prefix, idleTimeout, readTimeout, writeTimeout, "")
 
 		assertError(t, err, errorspkg.ServerInvalidPort)
 	})
@@ -25,7 +25,7 @@ func TestNewConfig(t *testing.T) {
 		port := 3001
 		prefix := ""
 
-		_, err := NewConfig(port, prefix, idleTimeout, readTimeout, writeTimeout)
+		_, err := NewConfig(port, prefix, idleTimeout, readTimeout, writeTimeout, "")
 
 		assertError(t, err, errorspkg.ServerMissingPrefix)
 	})
@@ -34,7 +34,7 @@ func TestNewConfig(t *testing.T) {
 		port := 3001
 		prefix := "/api"
 
-		_, err := NewConfig(port, prefix, idleTimeout, readTimeout, writeTimeout)
+		_, err := NewConfig(port, prefix, idleTimeout, readTimeout, writeTimeout, "")
 
 		assertNoError(t, err)
 	})
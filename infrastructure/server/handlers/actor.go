@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"users/domain"
+)
+
+// Actor stores the calling application, taken from the X-Application-ID header, in the request context
+// so the repository can attribute the changes it records.
+func Actor() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if actor := ctx.Request.Header.Get(xAppID); actor != "" {
+			ctx.Request = ctx.Request.WithContext(domain.WithActor(ctx.Request.Context(), actor))
+		}
+		ctx.Next()
+	}
+}
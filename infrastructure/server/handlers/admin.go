@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"users/domain/errors"
+)
+
+const xAdminKey = "X-Admin-Key"
+
+// Admin only lets through the requests whose X-Admin-Key header holds key. An empty key disables the
+// routes it guards.
+func Admin(key string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		given := ctx.Request.Header.Get(xAdminKey)
+		if key == "" || subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			ctx.Error(errors.AppAdminOnly)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdmin(t *testing.T) {
+	forbidden := "{\"type\":\"urn:users-api:problem:forbidden\",\"title\":\"Admin key required\",\"status\":403,\"detail\":\"app: missing or wrong admin key\",\"instance\":\"/1\"}"
+
+	tests := []struct {
+		name         string
+		key          string
+		header       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "on matching key",
+			key:          "secret",
+			header:       "secret",
+			expectedCode: http.StatusNoContent,
+			expectedBody: "",
+		},
+		{
+			name:         "on missing header",
+			key:          "secret",
+			header:       "",
+			expectedCode: http.StatusForbidden,
+			expectedBody: forbidden,
+		},
+		{
+			name:         "on wrong key",
+			key:          "secret",
+			header:       "guess",
+			expectedCode: http.StatusForbidden,
+			expectedBody: forbidden,
+		},
+		{
+			name:         "on admin routes disabled",
+			key:          "",
+			header:       "",
+			expectedCode: http.StatusForbidden,
+			expectedBody: forbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/1"
+
+			request, _ := http.NewRequest(http.MethodDelete, url, nil)
+			if test.header != "" {
+				request.Header.Set(xAdminKey, test.header)
+			}
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.DELETE(url, Admin(test.key), func(ctx *gin.Context) {
+				ctx.Status(http.StatusNoContent)
+			})
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
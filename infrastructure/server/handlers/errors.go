@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/server/requests"
+)
+
+const (
+	mimeProblem       = "application/problem+json"
+	problemTypePrefix = "urn:users-api:problem:"
+)
+
+// Problem is an RFC 7807 problem details object. Errors lists the invalid fields of the request payload.
+type Problem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	TraceID  string                `json:"trace_id,omitempty"`
+	Errors   []requests.FieldError `json:"errors,omitempty"`
+}
+
+type problemKind struct {
+	slug   string
+	title  string
+	status int
+}
+
+var (
+	problemInvalidRequest  = problemKind{"invalid-request", "Invalid request", http.StatusBadRequest}
+	problemUnsupportedType = problemKind{"unsupported-media-type", "Unsupported media type", http.StatusUnsupportedMediaType}
+	problemInternal        = problemKind{"internal-error", "Internal error", http.StatusInternalServerError}
+)
+
+// appProblems maps the domain errors to the problem reported to clients.
+var appProblems = map[errorspkg.AppError]problemKind{
+	errorspkg.AppUserNotFound:    {"user-not-found", "User not found", http.StatusNotFound},
+	errorspkg.AppUserNotDeleted:  {"user-not-deleted", "User not in the trash", http.StatusConflict},
+	errorspkg.AppAdminOnly:       {"forbidden", "Admin key required", http.StatusForbidden},
+	errorspkg.AppUserExists:      {"user-exists", "User already exists", http.StatusConflict},
+	errorspkg.AppEmailTaken:      {"email-taken", "Email already taken", http.StatusConflict},
+	errorspkg.AppInvalidCursor:   {"invalid-cursor", "Invalid cursor", http.StatusBadRequest},
+	errorspkg.AppVersionMismatch: {"version-mismatch", "Version mismatch", http.StatusPreconditionFailed},
+	errorspkg.AppBatchAborted:    {"batch-aborted", "Batch aborted", http.StatusFailedDependency},
+	errorspkg.AppPatchTestFailed: {"patch-test-failed", "Patch test failed", http.StatusConflict},
+	errorspkg.AppTimeout:         {"timeout", "Storage timeout", http.StatusGatewayTimeout},
+}
+
+// pgProblems maps Postgres error codes that are caused by the request rather than by the server.
+var pgProblems = map[string]problemKind{
+	"23505": {"conflict", "Conflicting value", http.StatusConflict},
+	"23503": {"conflict", "Conflicting value", http.StatusConflict},
+	"23514": {"invalid-value", "Invalid value", http.StatusUnprocessableEntity},
+	"23502": {"invalid-value", "Invalid value", http.StatusUnprocessableEntity},
+	"22001": {"invalid-value", "Invalid value", http.StatusUnprocessableEntity},
+	"22007": {"invalid-value", "Invalid value", http.StatusUnprocessableEntity},
+	"22008": {"invalid-value", "Invalid value", http.StatusUnprocessableEntity},
+	"40001": {"concurrent-update", "Concurrent update", http.StatusConflict},
+	"40P01": {"concurrent-update", "Concurrent update", http.StatusConflict},
+}
+
+// Errors renders the last error added to the context with ctx.Error as an application/problem+json
+// response. Errors of type gin.ErrorTypeBind are client errors, reported as 400 unless their meta holds
+// another status, along with the invalid fields when they are known. Internal and database errors are
+// reported without details, which are left to the trace.
+func Errors() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		last := ctx.Errors.Last()
+		if last == nil || ctx.Writer.Written() {
+			return
+		}
+
+		problem := toProblem(last)
+		problem.Instance = ctx.Request.URL.Path
+
+		span := trace.SpanFromContext(ctx.Request.Context())
+		if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+			problem.TraceID = spanContext.TraceID().String()
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(last.Err, &pgErr) && pgErr.Detail != "" {
+			span.SetAttributes(attribute.String("db.error.detail", pgErr.Detail))
+		}
+
+		ctx.Header("Content-Type", mimeProblem)
+		ctx.JSON(problem.Status, problem)
+	}
+}
+
+func toProblem(err *gin.Error) Problem {
+	if err.IsType(gin.ErrorTypeBind) {
+		kind := problemInvalidRequest
+		if status, ok := err.Meta.(int); ok && status == http.StatusUnsupportedMediaType {
+			kind = problemUnsupportedType
+		}
+		problem := kind.problem(err.Err.Error())
+		problem.Errors = requests.FieldErrors(err.Err)
+		return problem
+	}
+
+	kind := problemKindFor(err.Err)
+	if kind.status == http.StatusInternalServerError {
+		return kind.problem("")
+	}
+
+	// The detail of a Postgres error quotes the values involved, which may belong to other users.
+	var pgErr *pgconn.PgError
+	if errors.As(err.Err, &pgErr) {
+		return kind.problem("")
+	}
+
+	return kind.problem(err.Err.Error())
+}
+
+// describeError reports an error that is part of a successful response, such as the failure of a single
+// item of a batch, with the status and detail Errors would give it.
+func describeError(err error) (int, string) {
+	problem := toProblem(&gin.Error{Err: err, Type: gin.ErrorTypePrivate})
+	if problem.Detail == "" {
+		return problem.Status, problem.Title
+	}
+	return problem.Status, problem.Detail
+}
+
+func problemKindFor(err error) problemKind {
+	var appErr errorspkg.AppError
+	if errors.As(err, &appErr) {
+		if kind, ok := appProblems[appErr]; ok {
+			return kind
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if kind, ok := pgProblems[pgErr.Code]; ok {
+			return kind
+		}
+	}
+
+	return problemInternal
+}
+
+func (k problemKind) problem(detail string) Problem {
+	return Problem{
+		Type:   problemTypePrefix + k.slug,
+		Title:  k.title,
+		Status: k.status,
+		Detail: detail,
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	errorspkg "users/domain/errors"
+)
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      gin.HandlerFunc
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "on domain error",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(fmt.Errorf("get user: %w", errorspkg.AppUserNotFound))
+			},
+			expectedCode: http.StatusNotFound,
+			expectedBody: "{\"type\":\"urn:users-api:problem:user-not-found\",\"title\":\"User not found\",\"status\":404,\"detail\":\"get user: app: user not found\",\"instance\":\"/1\"}",
+		},
+		{
+			name: "on timeout",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(fmt.Errorf("%w: %w", errorspkg.AppTimeout, context.DeadlineExceeded))
+			},
+			expectedCode: http.StatusGatewayTimeout,
+			expectedBody: "{\"type\":\"urn:users-api:problem:timeout\",\"title\":\"Storage timeout\",\"status\":504,\"detail\":\"app: storage did not answer in time: context deadline exceeded\",\"instance\":\"/1\"}",
+		},
+		{
+			name: "on unique violation",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(&pgconn.PgError{Code: "23505", Message: "duplicate key value", Detail: "Key (email)=(test@test.com) already exists."})
+			},
+			expectedCode: http.StatusConflict,
+			expectedBody: "{\"type\":\"urn:users-api:problem:conflict\",\"title\":\"Conflicting value\",\"status\":409,\"instance\":\"/1\"}",
+		},
+		{
+			name: "on bind error",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(errors.New("invalid body")).SetType(gin.ErrorTypeBind)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"invalid body\",\"instance\":\"/1\"}",
+		},
+		{
+			name: "on unsupported content type",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(errors.New("unsupported content type")).SetType(gin.ErrorTypeBind).SetMeta(http.StatusUnsupportedMediaType)
+			},
+			expectedCode: http.StatusUnsupportedMediaType,
+			expectedBody: "{\"type\":\"urn:users-api:problem:unsupported-media-type\",\"title\":\"Unsupported media type\",\"status\":415,\"detail\":\"unsupported content type\",\"instance\":\"/1\"}",
+		},
+		{
+			name: "on internal error",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(errors.New("connection refused"))
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\"}",
+		},
+		{
+			name: "on response already written",
+			handler: func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, "partial")
+				_ = ctx.Error(errors.New("connection reset"))
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "partial",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, "/1", nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.GET("/:id", test.handler)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
+
+func TestErrorsTraceID(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+
+	request, _ := http.NewRequest(http.MethodGet, "/1", nil)
+	request = request.WithContext(trace.ContextWithSpanContext(request.Context(), spanContext))
+	response := httptest.NewRecorder()
+
+	router := gin.New()
+	router.Use(Errors())
+	router.GET("/:id", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("connection refused"))
+	})
+	router.ServeHTTP(response, request)
+
+	assertString(t, response.Header().Get("Content-Type"), mimeProblem)
+	assertString(t, response.Body.String(), "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\",\"trace_id\":\"4bf92f3577b34da6a3ce929d0e0e4736\"}")
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"slices"
+	"strconv"
+	"strings"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+)
+
+const (
+	ifMatch     = "If-Match"
+	ifNoneMatch = "If-None-Match"
+)
+
+// ifMatchVersion returns the user version required by the If-Match header, a comma-separated list of
+// entity tags. Strong comparison is used, as required for If-Match, so weak tags never match.
+// It returns errorspkg.AppVersionMismatch when no tag can match, and an error when the tags name several
+// versions, since a user is modified only if it still has the version it was read with.
+func ifMatchVersion(ctx *gin.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader(ifMatch))
+	if header == "" || header == "*" {
+		return entities.AnyVersion, nil
+	}
+
+	var versions []int64
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" || strings.HasPrefix(value, "W/") {
+			continue
+		}
+
+		value, err := strconv.Unquote(value)
+		if err != nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+
+		if !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+
+	switch len(versions) {
+	case 0:
+		return 0, errorspkg.AppVersionMismatch
+	case 1:
+		return versions[0], nil
+	default:
+		return 0, fmt.Errorf("the If-Match header lists %d versions of the user, it must list a single entity tag", len(versions))
+	}
+}
+
+// checkIfMatch returns the version required by the If-Match header or reports why it cannot be used.
+func checkIfMatch(ctx *gin.Context, span trace.Span) (int64, bool) {
+	version, err := ifMatchVersion(ctx)
+	if err == nil {
+		return version, true
+	}
+
+	if errors.Is(err, errorspkg.AppVersionMismatch) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+	} else {
+		bindError(ctx, span, err)
+	}
+
+	return 0, false
+}
+
+// etagMatches reports whether the If-None-Match header lists the given entity tag.
+// Weak comparison is used, as required for If-None-Match.
+func etagMatches(ctx *gin.Context, etag string) bool {
+	for _, value := range strings.Split(ctx.GetHeader(ifNoneMatch), ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
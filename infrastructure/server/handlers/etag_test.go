@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		ifMatch         string
+		expectedVersion int64
+		expectedErr     error
+		expectedInvalid bool
+	}{
+		{name: "on missing header", expectedVersion: entities.AnyVersion},
+		{name: "on any version", ifMatch: "*", expectedVersion: entities.AnyVersion},
+		{name: "on single tag", ifMatch: "\"3\"", expectedVersion: 3},
+		{name: "on list with a single version", ifMatch: "\"3\", W/\"4\", \"3\"", expectedVersion: 3},
+		{name: "on list with unknown tags", ifMatch: "\"abc\",, \"3\"", expectedVersion: 3},
+		{name: "on weak tag", ifMatch: "W/\"3\"", expectedErr: errorspkg.AppVersionMismatch},
+		{name: "on unquoted tag", ifMatch: "3", expectedErr: errorspkg.AppVersionMismatch},
+		{name: "on several versions", ifMatch: "\"3\", \"4\"", expectedInvalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request, _ = http.NewRequest(http.MethodPut, "/1", nil)
+			ctx.Request.Header.Set(ifMatch, test.ifMatch)
+
+			version, err := ifMatchVersion(ctx)
+
+			if test.expectedInvalid {
+				if err == nil || errors.Is(err, errorspkg.AppVersionMismatch) {
+					t.Errorf("got '%v', want an invalid request error", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("got '%v', want '%v'", err, test.expectedErr)
+			}
+			assertInt(t, int(version), int(test.expectedVersion))
+		})
+	}
+}
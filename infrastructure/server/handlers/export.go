@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+	"users/domain/entities"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+const (
+	contentDisposition = "Content-Disposition"
+	xExportError       = "X-Export-Error"
+)
+
+var exportContentTypes = map[string]string{
+	requests.ExportCSV:    "text/csv; charset=utf-8",
+	requests.ExportNDJSON: "application/x-ndjson",
+}
+
+// Export godoc
+// @Summary     Export users
+// @Description Streams every matching user as CSV or NDJSON (one JSON object per line).
+// @Id          Export
+// @Produce     text/csv
+// @Produce     application/x-ndjson
+// @Param       format query string false "Export format: 'csv' (default) or 'ndjson'."
+// @Param       sort query string false "Comma-separated sort keys among 'name' (default), 'created_at', 'updated_at', 'birth' and 'email'; prefix a key with '-' to sort descending."
+// @Param       active query string false "Filter by status: 'true' (default), 'false' or 'any'."
+// @Param       location query string false "Filter by location (case-insensitive)."
+// @Param       email_domain query string false "Filter by email domain, e.g. 'example.com'."
+// @Param       created_from query string false "Created at or after, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       created_to query string false "Created at or before, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       updated_from query string false "Updated at or after, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       updated_to query string false "Updated at or before, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       birth_from query string false "Born on or after, as 'DD/MM/YYYY'."
+// @Param       birth_to query string false "Born on or before, as 'DD/MM/YYYY'."
+// @Success     200 {file} file
+// @Header      200 {string} Content-Disposition "attachment; filename=\"users.csv\""
+// @Header      200 {string} X-Export-Error "Trailer set when the export failed after it started; the file then ends with an error line."
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/export [get]
+func (h *Handlers) Export(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Export")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	var query requests.ExportUsers
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	filter, err := query.ToFilter()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	sort, err := query.ToSort()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	format := query.ToFormat()
+
+	var encoder responses.UserEncoder
+	if format == requests.ExportNDJSON {
+		encoder = responses.NewNDJSONEncoder(ctx.Writer)
+	} else {
+		encoder = responses.NewCSVEncoder(ctx.Writer)
+	}
+
+	ctx.Header("Content-Type", exportContentTypes[format])
+	ctx.Header(contentDisposition, fmt.Sprintf("attachment; filename=%q", "users."+format))
+	ctx.Header("Trailer", xExportError)
+
+	count := 0
+	err = h.actions.Export(tracerCtx, filter, sort, func(user *entities.User) error {
+		count++
+		return encoder.Encode(user)
+	})
+	if err == nil {
+		err = encoder.Close()
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.query", ctx.Request.URL.RawQuery))
+	span.SetAttributes(attribute.Int("http.response.export.count", count))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		// Once part of the file has been sent the status can no longer change, so the failure is reported by
+		// the last line of the file and by a trailer.
+		if ctx.Writer.Written() {
+			_, message := describeError(err)
+			if failErr := encoder.Fail(message); failErr != nil {
+				span.RecordError(failErr)
+			}
+			ctx.Writer.Header().Set(xExportError, message)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Writer.Header().Del(contentDisposition)
+		ctx.Writer.Header().Del("Trailer")
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
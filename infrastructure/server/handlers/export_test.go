@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/dependencies"
+)
+
+type ExportMock struct {
+	execute func(context.Context, entities.UserFilter, []entities.SortKey, func(*entities.User) error) error
+	answer  []*entities.User
+	err     error
+}
+
+func NewExportMock(answer []*entities.User, err error) *ExportMock {
+	mock := &ExportMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, filter entities.UserFilter, sort []entities.SortKey, fn func(*entities.User) error) error {
+		for _, user := range answer {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+
+	return mock
+}
+
+func TestExport(t *testing.T) {
+	birth := time.Date(1992, 9, 23, 0, 0, 0, 0, time.UTC)
+	email := "jane@test.com"
+	location := "Bogotá, \"CO\""
+
+	users := []*entities.User{
+		{ID: "1", Name: "Jane", Birth: &birth, Email: &email, Location: &location, Active: true},
+		{ID: "2", Name: "John"},
+	}
+
+	formula := "-2+3"
+	formulas := []*entities.User{
+		{ID: "3", Name: "=HYPERLINK(\"http://evil.test\")", Location: &formula},
+		{ID: "4", Name: "'@me"},
+		{ID: "5", Name: "O'Hara"},
+	}
+
+	tests := []struct {
+		name                string
+		export              *ExportMock
+		query               string
+		expectedCode        int
+		expectedType        string
+		expectedDisposition string
+		expectedTrailer     string
+		expectedBody        string
+	}{
+		{
+			name:                "on CSV export",
+			export:              NewExportMock(users, nil),
+			expectedCode:        http.StatusOK,
+			expectedType:        "text/csv; charset=utf-8",
+			expectedDisposition: "attachment; filename=\"users.csv\"",
+			expectedBody:        "id,name,birth,email,location,created_at,updated_at,active\n1,Jane,23/09/1992,jane@test.com,\"Bogotá, \"\"CO\"\"\",0001-01-01 00:00:00,0001-01-01 00:00:00,true\n2,John,,,,0001-01-01 00:00:00,0001-01-01 00:00:00,false\n",
+		},
+		{
+			name:                "on CSV export of formulas",
+			export:              NewExportMock(formulas, nil),
+			expectedCode:        http.StatusOK,
+			expectedType:        "text/csv; charset=utf-8",
+			expectedDisposition: "attachment; filename=\"users.csv\"",
+			expectedBody:        "id,name,birth,email,location,created_at,updated_at,active\n3,\"'=HYPERLINK(\"\"http://evil.test\"\")\",,,'-2+3,0001-01-01 00:00:00,0001-01-01 00:00:00,false\n4,''@me,,,,0001-01-01 00:00:00,0001-01-01 00:00:00,false\n5,O'Hara,,,,0001-01-01 00:00:00,0001-01-01 00:00:00,false\n",
+		},
+		{
+			name:                "on empty CSV export",
+			export:              NewExportMock(nil, nil),
+			query:               "?format=csv",
+			expectedCode:        http.StatusOK,
+			expectedType:        "text/csv; charset=utf-8",
+			expectedDisposition: "attachment; filename=\"users.csv\"",
+			expectedBody:        "id,name,birth,email,location,created_at,updated_at,active\n",
+		},
+		{
+			name:                "on NDJSON export",
+			export:              NewExportMock(users, nil),
+			query:               "?format=ndjson",
+			expectedCode:        http.StatusOK,
+			expectedType:        "application/x-ndjson",
+			expectedDisposition: "attachment; filename=\"users.ndjson\"",
+			expectedBody:        "{\"id\":\"1\",\"name\":\"Jane\",\"birth\":\"23/09/1992\",\"email\":\"jane@test.com\",\"location\":\"Bogotá, \\\"CO\\\"\",\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":true,\"etag\":\"\\\"0\\\"\"}\n{\"id\":\"2\",\"name\":\"John\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}\n",
+		},
+		{
+			name:         "on unknown format",
+			export:       NewExportMock(users, nil),
+			query:        "?format=xml",
+			expectedCode: http.StatusBadRequest,
+			expectedType: "application/problem+json",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"Key: 'ExportUsers.Format' Error:Field validation for 'Format' failed on the 'oneof' tag\",\"instance\":\"/export\"}",
+		},
+		{
+			name:         "on invalid filter",
+			export:       NewExportMock(users, nil),
+			query:        "?active=maybe",
+			expectedCode: http.StatusBadRequest,
+			expectedType: "application/problem+json",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'active' field from \\\"maybe\\\": strconv.ParseBool: parsing \\\"maybe\\\": invalid syntax\",\"instance\":\"/export\"}",
+		},
+		{
+			name:         "on invalid sort",
+			export:       NewExportMock(users, nil),
+			query:        "?sort=age",
+			expectedCode: http.StatusBadRequest,
+			expectedType: "application/problem+json",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'sort' field from \\\"age\\\": unknown sort field \\\"age\\\"\",\"instance\":\"/export\"}",
+		},
+		{
+			name:         "on repository error before any row",
+			export:       NewExportMock(nil, errors.New("an error occurred")),
+			expectedCode: http.StatusInternalServerError,
+			expectedType: "application/problem+json",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/export\"}",
+		},
+		{
+			name:                "on repository error after some rows",
+			export:              NewExportMock(users[:1], errorspkg.AppTimeout),
+			query:               "?format=ndjson",
+			expectedCode:        http.StatusOK,
+			expectedType:        "application/x-ndjson",
+			expectedDisposition: "attachment; filename=\"users.ndjson\"",
+			expectedTrailer:     "app: storage did not answer in time",
+			expectedBody:        "{\"id\":\"1\",\"name\":\"Jane\",\"birth\":\"23/09/1992\",\"email\":\"jane@test.com\",\"location\":\"Bogotá, \\\"CO\\\"\",\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":true,\"etag\":\"\\\"0\\\"\"}\n{\"error\":\"app: storage did not answer in time\"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/export"
+
+			actions := dependencies.Actions{Export: test.export.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodGet, url+test.query, nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.GET(url, handler.Export)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Header().Get("Content-Type"), test.expectedType)
+			assertString(t, response.Header().Get(contentDisposition), test.expectedDisposition)
+			assertString(t, response.Result().Trailer.Get(xExportError), test.expectedTrailer)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
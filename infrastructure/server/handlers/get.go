@@ -5,15 +5,30 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"net/http"
+	"users/infrastructure/server/requests"
 	"users/infrastructure/server/responses"
 )
 
 // Get godoc
-// @Summary     List active users
+// @Summary     List users
 // @Id          Get
 // @Produce     json
-// @Success     200 {array} responses.UserResponse
-// @Failure     500
+// @Param       limit query int false "Maximum number of users to return."
+// @Param       cursor query string false "The 'next_cursor' value of the previous page."
+// @Param       sort query string false "Comma-separated sort keys among 'name' (default), 'created_at', 'updated_at', 'birth' and 'email'; prefix a key with '-' to sort descending."
+// @Param       active query string false "Filter by status: 'true' (default), 'false' or 'any'."
+// @Param       location query string false "Filter by location (case-insensitive)."
+// @Param       email_domain query string false "Filter by email domain, e.g. 'example.com'."
+// @Param       created_from query string false "Created at or after, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       created_to query string false "Created at or before, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       updated_from query string false "Updated at or after, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       updated_to query string false "Updated at or before, as 'YYYY-MM-DD hh:mm:ss'."
+// @Param       birth_from query string false "Born on or after, as 'DD/MM/YYYY'."
+// @Param       birth_to query string false "Born on or before, as 'DD/MM/YYYY'."
+// @Success     200 {object} responses.UserPage
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
 // @Router      /users [get]
 func (h *Handlers) Get(ctx *gin.Context) {
 	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Get")
@@ -21,16 +36,41 @@ func (h *Handlers) Get(ctx *gin.Context) {
 
 	headers := mapToString(ctx.Request.Header)
 
-	result, err := h.actions.Get(tracerCtx)
+	var query requests.ListUsers
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	filter, err := query.ToFilter()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	page, err := query.ToPageRequest()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.actions.Get(tracerCtx, filter, page)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
 	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.query", ctx.Request.URL.RawQuery))
 
-	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUserList(result)})
+	ctx.JSON(http.StatusOK, responses.FromUserPage(result.Users, requests.EncodeCursor(result.Next)))
 }
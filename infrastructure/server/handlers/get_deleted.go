@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+	"users/infrastructure/server/responses"
+)
+
+// GetDeleted godoc
+// @Summary     List deleted users
+// @Id          GetDeleted
+// @Produce     json
+// @Success     200 {array} responses.UserResponse
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/deleted [get]
+func (h *Handlers) GetDeleted(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-GetDeleted")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	result, err := h.actions.GetDeleted(tracerCtx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUserList(result)})
+}
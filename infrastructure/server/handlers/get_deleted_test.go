@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"users/domain/entities"
+	"users/infrastructure/dependencies"
+)
+
+type GetDeletedMock struct {
+	execute func(context.Context) ([]*entities.User, error)
+	answer  []*entities.User
+	err     error
+}
+
+func NewGetDeletedMock(answer []*entities.User, err error) *GetDeletedMock {
+	mock := &GetDeletedMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context) ([]*entities.User, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestGetDeleted(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		getDeleted   *GetDeletedMock
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "on OK execution",
+			getDeleted:   NewGetDeletedMock([]*entities.User{{ID: "1", DeletedAt: &deletedAt}}, nil),
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\",\"deleted_at\":\"2025-01-02 03:04:05\"}]}",
+		},
+		{
+			name:         "on repository error",
+			getDeleted:   NewGetDeletedMock(nil, errors.New("an error occurred")),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/deleted\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/deleted"
+
+			actions := dependencies.Actions{GetDeleted: test.getDeleted.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodGet, url, nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.GET(url, handler.GetDeleted)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
@@ -16,8 +16,9 @@ import (
 // @Produce     json
 // @Param       request body requests.MultipleIDRequest true "Enter the IDs of the users to list."
 // @Success     200 {array} responses.UserResponse
-// @Failure     400 {object} error "error"
-// @Failure     500 {object} error "error"
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
 // @Router      /users/search [post]
 func (h *Handlers) GetMultiple(ctx *gin.Context) {
 	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-GetMultiple")
@@ -27,9 +28,7 @@ func (h *Handlers) GetMultiple(ctx *gin.Context) {
 
 	var body requests.MultipleIDRequest
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": err.Error()})
+		bindError(ctx, span, requests.FromBindError(err, &body))
 		return
 	}
 
@@ -37,7 +36,7 @@ func (h *Handlers) GetMultiple(ctx *gin.Context) {
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -24,21 +24,28 @@ func TestGetMultiple(t *testing.T) {
 			getByID:      NewGetByIDMock([]*entities.User{{ID: "1"}, {ID: "2"}}, nil),
 			body:         []byte(`{"users":["1","2"]}`),
 			expectedCode: http.StatusOK,
-			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false},{\"id\":\"2\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false}]}",
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"},{\"id\":\"2\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}]}",
 		},
 		{
 			name:         "on invalid json",
 			getByID:      NewGetByIDMock(nil, errors.New("an error occurred")),
 			body:         []byte(`{"users":["1","2"]`),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"unexpected EOF\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"unexpected EOF\",\"instance\":\"/search\"}",
+		},
+		{
+			name:         "on ids of the wrong type",
+			getByID:      NewGetByIDMock(nil, errors.New("an error occurred")),
+			body:         []byte(`{"users":"1"}`),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'users' field must be of type []string\",\"instance\":\"/search\",\"errors\":[{\"field\":\"users\",\"code\":\"invalid_type\",\"message\":\"'users' field must be of type []string\"}]}",
 		},
 		{
 			name:         "on repository error",
 			getByID:      NewGetByIDMock(nil, errors.New("an error occurred")),
 			body:         []byte(`{"users":["1","2"]}`),
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: "{\"errors\":\"an error occurred\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/search\"}",
 		},
 	}
 
@@ -53,6 +60,7 @@ func TestGetMultiple(t *testing.T) {
 			response := httptest.NewRecorder()
 
 			router := gin.New()
+			router.Use(Errors())
 			router.POST(url, handler.GetMultiple)
 			router.ServeHTTP(response, request)
 
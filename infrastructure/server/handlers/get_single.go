@@ -5,6 +5,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"net/http"
+	"strings"
+	"users/domain"
 	"users/infrastructure/server/responses"
 )
 
@@ -13,9 +15,14 @@ import (
 // @Id          GetSingle
 // @Produce     json
 // @Param       id path string true "User ID"
+// @Param       If-None-Match header string false "Skip the body if the user's ETag matches. The user is then read from the primary database."
+// @Param       Cache-Control header string false "'no-cache' reads the user from the primary database, so that it reflects the client's last write."
 // @Success     200 {object} responses.UserResponse
-// @Failure     400 {object} error "error"
-// @Failure     500 {object} error "error"
+// @Header      200 {string} ETag "The version of the user."
+// @Success     304
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
 // @Router      /users/search/{id} [get]
 func (h *Handlers) GetSingle(ctx *gin.Context) {
 	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-GetSingle")
@@ -25,11 +32,16 @@ func (h *Handlers) GetSingle(ctx *gin.Context) {
 
 	id := ctx.Param("id")
 
+	// A replica may lag behind the client's last write, so clients that need to see it ask for the primary.
+	if readsOwnWrites(ctx) {
+		tracerCtx = domain.WithPrimary(tracerCtx)
+	}
+
 	result, err := h.actions.GetByID(tracerCtx, []string{id})
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -37,5 +49,30 @@ func (h *Handlers) GetSingle(ctx *gin.Context) {
 	span.SetAttributes(attribute.String("http.headers", headers))
 	span.SetAttributes(attribute.String("http.path.id", id))
 
+	if len(result) == 1 {
+		etag := responses.ETag(result[0])
+		ctx.Header("ETag", etag)
+
+		if etagMatches(ctx, etag) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUserList(result)})
 }
+
+// readsOwnWrites reports whether the request must see every committed write: either it revalidates an
+// ETag, which may come from a write the replicas have not applied yet, or it asks for no cached answer.
+func readsOwnWrites(ctx *gin.Context) bool {
+	if ctx.GetHeader(ifNoneMatch) != "" {
+		return true
+	}
+
+	for _, directive := range strings.Split(ctx.GetHeader("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+			return true
+		}
+	}
+	return false
+}
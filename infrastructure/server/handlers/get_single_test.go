@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"users/domain"
 	"users/domain/entities"
 	"users/infrastructure/dependencies"
 )
@@ -37,20 +38,39 @@ func TestGetSingle(t *testing.T) {
 	tests := []struct {
 		name         string
 		getByID      *GetByIDMock
+		ifNoneMatch  string
 		expectedCode int
+		expectedETag string
 		expectedBody string
 	}{
 		{
 			name:         "on OK execution",
-			getByID:      NewGetByIDMock([]*entities.User{{ID: "1"}}, nil),
+			getByID:      NewGetByIDMock([]*entities.User{{ID: "1", Version: 2}}, nil),
 			expectedCode: http.StatusOK,
-			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false}]}",
+			expectedETag: "\"2\"",
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}]}",
+		},
+		{
+			name:         "on matching If-None-Match",
+			getByID:      NewGetByIDMock([]*entities.User{{ID: "1", Version: 2}}, nil),
+			ifNoneMatch:  "W/\"1\", \"2\"",
+			expectedCode: http.StatusNotModified,
+			expectedETag: "\"2\"",
+			expectedBody: "",
+		},
+		{
+			name:         "on stale If-None-Match",
+			getByID:      NewGetByIDMock([]*entities.User{{ID: "1", Version: 2}}, nil),
+			ifNoneMatch:  "\"1\"",
+			expectedCode: http.StatusOK,
+			expectedETag: "\"2\"",
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}]}",
 		},
 		{
 			name:         "on repository error",
 			getByID:      NewGetByIDMock(nil, errors.New("an error occurred")),
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: "{\"errors\":\"an error occurred\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/search/1\"}",
 		},
 	}
 
@@ -62,14 +82,68 @@ func TestGetSingle(t *testing.T) {
 			handler := New(&actions)
 
 			request, _ := http.NewRequest(http.MethodGet, url, nil)
+			request.Header.Set(ifNoneMatch, test.ifNoneMatch)
 			response := httptest.NewRecorder()
 
 			router := gin.New()
+			router.Use(Errors())
 			router.GET(url, handler.GetSingle)
 			router.ServeHTTP(response, request)
 
 			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Header().Get("ETag"), test.expectedETag)
 			assertString(t, response.Body.String(), test.expectedBody)
 		})
 	}
 }
+
+func TestGetSingleReadsFromPrimary(t *testing.T) {
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		expectedPrimary bool
+	}{
+		{
+			name: "on plain request",
+		},
+		{
+			name:            "on If-None-Match",
+			headers:         map[string]string{ifNoneMatch: "\"2\""},
+			expectedPrimary: true,
+		},
+		{
+			name:            "on Cache-Control no-cache",
+			headers:         map[string]string{"Cache-Control": "max-age=0, No-Cache"},
+			expectedPrimary: true,
+		},
+		{
+			name:    "on other Cache-Control",
+			headers: map[string]string{"Cache-Control": "max-age=0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primary := false
+			actions := dependencies.Actions{GetByID: func(ctx context.Context, ids []string) ([]*entities.User, error) {
+				primary = domain.ReadsFromPrimary(ctx)
+				return []*entities.User{{ID: "1", Version: 2}}, nil
+			}}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodGet, "/search/1", nil)
+			for key, value := range test.headers {
+				request.Header.Set(key, value)
+			}
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.GET("/search/:id", handler.GetSingle)
+			router.ServeHTTP(response, request)
+
+			if primary != test.expectedPrimary {
+				t.Errorf("got a read from the primary '%t', want '%t'", primary, test.expectedPrimary)
+			}
+		})
+	}
+}
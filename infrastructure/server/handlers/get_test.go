@@ -12,18 +12,18 @@ import (
 )
 
 type GetMock struct {
-	execute func(context.Context) ([]*entities.User, error)
-	answer  []*entities.User
+	execute func(context.Context, entities.UserFilter, entities.PageRequest) (*entities.Page, error)
+	answer  *entities.Page
 	err     error
 }
 
-func NewGetMock(answer []*entities.User, err error) *GetMock {
+func NewGetMock(answer *entities.Page, err error) *GetMock {
 	mock := &GetMock{
 		answer: answer,
 		err:    err,
 	}
 
-	mock.execute = func(ctx context.Context) ([]*entities.User, error) {
+	mock.execute = func(ctx context.Context, filter entities.UserFilter, page entities.PageRequest) (*entities.Page, error) {
 		if err != nil {
 			return nil, err
 		}
@@ -37,20 +37,84 @@ func TestGet(t *testing.T) {
 	tests := []struct {
 		name         string
 		get          *GetMock
+		query        string
 		expectedCode int
 		expectedBody string
 	}{
 		{
 			name:         "on OK execution",
-			get:          NewGetMock([]*entities.User{{ID: "1"}, {ID: "2"}}, nil),
+			get:          NewGetMock(&entities.Page{Users: []*entities.User{{ID: "1"}, {ID: "2"}}}, nil),
 			expectedCode: http.StatusOK,
-			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false},{\"id\":\"2\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false}]}",
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"},{\"id\":\"2\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}],\"next_cursor\":null}",
+		},
+		{
+			name:         "on OK execution with next page",
+			get:          NewGetMock(&entities.Page{Users: []*entities.User{{ID: "1", Name: "a"}}, Next: &entities.Cursor{Values: []string{"a"}, ID: "1"}}, nil),
+			query:        "?limit=1",
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"a\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}],\"next_cursor\":\"eyJ2IjpbImEiXSwiaSI6IjEifQ\"}",
+		},
+		{
+			name:         "on invalid limit",
+			get:          NewGetMock(nil, nil),
+			query:        "?limit=-1",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"Key: 'ListUsers.Limit' Error:Field validation for 'Limit' failed on the 'min' tag\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on invalid cursor",
+			get:          NewGetMock(nil, nil),
+			query:        "?cursor=!",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'cursor' field from \\\"!\\\": illegal base64 data at input byte 0\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on unknown sort field",
+			get:          NewGetMock(nil, nil),
+			query:        "?sort=name,-age",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'sort' field from \\\"name,-age\\\": unknown sort field \\\"age\\\"\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on cursor not matching sort",
+			get:          NewGetMock(nil, nil),
+			query:        "?sort=-created_at,name&cursor=eyJ2IjpbImEiXSwiaSI6IjEifQ",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'cursor' field from \\\"eyJ2IjpbImEiXSwiaSI6IjEifQ\\\": app: cursor does not match the sort order\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on cursor from another sort",
+			get:          NewGetMock(nil, nil),
+			query:        "?sort=email&cursor=eyJ2IjpbIkFubiJdLCJpIjoiMSIsInMiOiJuYW1lIn0",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'cursor' field from \\\"eyJ2IjpbIkFubiJdLCJpIjoiMSIsInMiOiJuYW1lIn0\\\": app: cursor does not match the sort order\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on cursor from another sort with a value of another type",
+			get:          NewGetMock(nil, nil),
+			query:        "?sort=created_at&cursor=eyJ2IjpbIkFubiJdLCJpIjoiMSIsInMiOiJuYW1lIn0",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'cursor' field from \\\"eyJ2IjpbIkFubiJdLCJpIjoiMSIsInMiOiJuYW1lIn0\\\": app: cursor does not match the sort order\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on invalid active filter",
+			get:          NewGetMock(nil, nil),
+			query:        "?active=maybe",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'active' field from \\\"maybe\\\": strconv.ParseBool: parsing \\\"maybe\\\": invalid syntax\",\"instance\":\"/\"}",
+		},
+		{
+			name:         "on invalid birth range",
+			get:          NewGetMock(nil, nil),
+			query:        "?birth_from=1992-09-23",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'birth_from' field from \\\"1992-09-23\\\": parsing time \\\"1992-09-23\\\" as \\\"02/01/2006\\\": cannot parse \\\"92-09-23\\\" as \\\"/\\\"\",\"instance\":\"/\"}",
 		},
 		{
 			name:         "on repository error",
 			get:          NewGetMock(nil, errors.New("an error occurred")),
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: "{\"errors\":\"an error occurred\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/\"}",
 		},
 	}
 
@@ -61,10 +125,11 @@ func TestGet(t *testing.T) {
 			actions := dependencies.Actions{Get: test.get.execute}
 			handler := New(&actions)
 
-			request, _ := http.NewRequest(http.MethodGet, url, nil)
+			request, _ := http.NewRequest(http.MethodGet, url+test.query, nil)
 			response := httptest.NewRecorder()
 
 			router := gin.New()
+			router.Use(Errors())
 			router.GET(url, handler.Get)
 			router.ServeHTTP(response, request)
 
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+	"users/infrastructure/server/responses"
+)
+
+// GetHistory godoc
+// @Summary     List the changes made to a user
+// @Description Users in the trash keep their history; unknown and purged users answer 404.
+// @Id          GetHistory
+// @Produce     json
+// @Param       id path string true "User ID"
+// @Success     200 {array} responses.UserHistoryResponse
+// @Failure     404 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/{id}/history [get]
+func (h *Handlers) GetHistory(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-GetHistory")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	id := ctx.Param("id")
+
+	result, err := h.actions.GetHistory(tracerCtx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.path.id", id))
+	span.SetAttributes(attribute.Int("http.response.history.count", len(result)))
+
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUserHistoryList(result)})
+}
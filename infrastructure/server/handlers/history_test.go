@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"users/domain"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/dependencies"
+)
+
+type GetHistoryMock struct {
+	execute func(context.Context, string) ([]*entities.UserHistory, error)
+	answer  []*entities.UserHistory
+	err     error
+}
+
+func NewGetHistoryMock(answer []*entities.UserHistory, err error) *GetHistoryMock {
+	mock := &GetHistoryMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, id string) ([]*entities.UserHistory, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestGetHistory(t *testing.T) {
+	actor := "back-office"
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		getHistory   *GetHistoryMock
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "on OK execution",
+			getHistory: NewGetHistoryMock([]*entities.UserHistory{{
+				ID:            1,
+				UserID:        "1",
+				Action:        entities.HistoryCreated,
+				After:         &entities.User{ID: "1", Name: "Jane"},
+				ChangedFields: []string{"name"},
+				Actor:         &actor,
+				CreatedAt:     createdAt,
+			}}, nil),
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":[{\"id\":1,\"user_id\":\"1\",\"action\":\"created\",\"before\":null,\"after\":{\"id\":\"1\",\"name\":\"Jane\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"},\"changed_fields\":[\"name\"],\"actor\":\"back-office\",\"trace_id\":null,\"created_at\":\"2025-01-02 03:04:05\"}]}",
+		},
+		{
+			name:         "on empty history",
+			getHistory:   NewGetHistoryMock([]*entities.UserHistory{}, nil),
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":[]}",
+		},
+		{
+			name:         "on missing user",
+			getHistory:   NewGetHistoryMock(nil, errorspkg.AppUserNotFound),
+			expectedCode: http.StatusNotFound,
+			expectedBody: "{\"type\":\"urn:users-api:problem:user-not-found\",\"title\":\"User not found\",\"status\":404,\"detail\":\"app: user not found\",\"instance\":\"/1/history\"}",
+		},
+		{
+			name:         "on repository error",
+			getHistory:   NewGetHistoryMock(nil, errors.New("an error occurred")),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1/history\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/1/history"
+
+			actions := dependencies.Actions{GetHistory: test.getHistory.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodGet, url, nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.GET(url, handler.GetHistory)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name          string
+		appID         string
+		expectedActor string
+	}{
+		{name: "with application header", appID: "back-office", expectedActor: "back-office"},
+		{name: "without application header", appID: "", expectedActor: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actor string
+
+			router := gin.New()
+			router.Use(Actor())
+			router.GET("/", func(ctx *gin.Context) {
+				actor = domain.ActorFromContext(ctx.Request.Context())
+			})
+
+			request, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if test.appID != "" {
+				request.Header.Set(xAppID, test.appID)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			assertString(t, actor, test.expectedActor)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"io"
+	"net/http"
+	"slices"
+	"users/domain/entities"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+const importFileField = "file"
+
+// Import godoc
+// @Summary     Import users from a file
+// @Description Accepts a CSV file with a header line (columns 'name', 'birth', 'email' and 'location') or an NDJSON file
+// @Description with one user per line, either as the request body or as the 'file' field of a multipart form.
+// @Description Every line follows the rules of a single creation; invalid lines are reported and the others are created.
+// @Id          Import
+// @Accept      text/csv
+// @Accept      application/x-ndjson
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       file formData file false "The file to import, when sent as a multipart form."
+// @Param       format query string false "File format: 'csv' or 'ndjson'; guessed from the file name or the content type when missing."
+// @Param       dry_run query bool false "Only validate the file, without creating any user."
+// @Success     200 {object} responses.ImportReport
+// @Success     201 {object} responses.ImportReport
+// @Success     207 {object} responses.ImportReport
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/import [post]
+func (h *Handlers) Import(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Import")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	var query requests.ImportUsers
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var file io.Reader = ctx.Request.Body
+	var filename string
+
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		upload, header, err := ctx.Request.FormFile(importFileField)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			ctx.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+		defer upload.Close()
+
+		file, filename = upload, header.Filename
+	}
+
+	format, err := query.ToFormat(ctx.ContentType(), filename)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	lines, err := requests.ParseImport(file, format)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	report := &responses.ImportReport{
+		DryRun: query.DryRun,
+		Total:  len(lines),
+		Errors: []*responses.ImportLineFailure{},
+	}
+
+	var users []*entities.User
+	var positions []int
+	for i, line := range lines {
+		if line.Err != nil {
+			report.Errors = append(report.Errors, &responses.ImportLineFailure{Line: line.Line, Errors: line.Err.Error()})
+			continue
+		}
+		users = append(users, line.User)
+		positions = append(positions, i)
+	}
+
+	report.Valid = len(users)
+
+	if !query.DryRun && len(users) > 0 {
+		results, err := h.actions.Import(tracerCtx, users)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			ctx.Error(err)
+			return
+		}
+
+		for j, r := range results {
+			if r.Err != nil {
+				line := lines[positions[j]].Line
+				_, detail := describeError(r.Err)
+				report.Errors = append(report.Errors, &responses.ImportLineFailure{Line: line, Errors: detail})
+				continue
+			}
+			report.Created++
+		}
+
+		sortImportFailures(report.Errors)
+	}
+
+	report.Failed = len(report.Errors)
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.query", ctx.Request.URL.RawQuery))
+	span.SetAttributes(attribute.Int("http.response.import.total", report.Total))
+	span.SetAttributes(attribute.Int("http.response.import.created", report.Created))
+	span.SetAttributes(attribute.Int("http.response.import.failed", report.Failed))
+
+	switch {
+	case query.DryRun:
+		ctx.JSON(http.StatusOK, gin.H{"data": report})
+	case report.Failed == 0:
+		ctx.JSON(http.StatusCreated, gin.H{"data": report})
+	default:
+		ctx.JSON(http.StatusMultiStatus, gin.H{"data": report})
+	}
+}
+
+func sortImportFailures(failures []*responses.ImportLineFailure) {
+	slices.SortStableFunc(failures, func(a, b *responses.ImportLineFailure) int {
+		return a.Line - b.Line
+	})
+}
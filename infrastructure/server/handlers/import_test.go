@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	"users/infrastructure/dependencies"
+)
+
+type ImportMock struct {
+	execute func(context.Context, []*entities.User) ([]*entities.BatchResult, error)
+	calls   int
+}
+
+// NewImportMock creates every user it receives, except those named in failures.
+func NewImportMock(failures map[string]error) *ImportMock {
+	mock := &ImportMock{}
+
+	mock.execute = func(ctx context.Context, users []*entities.User) ([]*entities.BatchResult, error) {
+		mock.calls++
+		results := make([]*entities.BatchResult, len(users))
+		for i, user := range users {
+			if err, ok := failures[user.Name]; ok {
+				results[i] = &entities.BatchResult{Err: err}
+			} else {
+				results[i] = &entities.BatchResult{User: user}
+			}
+		}
+		return results, nil
+	}
+
+	return mock
+}
+
+func multipartFile(t *testing.T, filename string, content string) (io.Reader, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile(importFileField, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = part.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &body, writer.FormDataContentType()
+}
+
+func TestImport(t *testing.T) {
+	upload, uploadType := multipartFile(t, "legacy.csv", "name,email\nJane,jane@test.com\n")
+
+	tests := []struct {
+		name          string
+		importUsers   *ImportMock
+		query         string
+		contentType   string
+		body          io.Reader
+		expectedCalls int
+		expectedCode  int
+		expectedBody  string
+	}{
+		{
+			name:          "on CSV body",
+			importUsers:   NewImportMock(nil),
+			contentType:   "text/csv",
+			body:          bytes.NewReader([]byte("name,birth,email,location\nJane,23/09/1992,jane@test.com,Bogotá\nJohn,,,\n")),
+			expectedCalls: 1,
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "{\"data\":{\"dry_run\":false,\"total\":2,\"valid\":2,\"created\":2,\"failed\":0,\"errors\":[]}}",
+		},
+		{
+			name:          "on CSV body with invalid lines",
+			importUsers:   NewImportMock(map[string]error{"Jim": &pgconn.PgError{Code: "23505", Detail: "Key (email)=(jim@test.com) already exists."}}),
+			contentType:   "text/csv",
+			body:          bytes.NewReader([]byte("name,birth\nJane,23/09/92\nJim,\n,01/01/2000\nJohn,\"\n")),
+			expectedCalls: 1,
+			expectedCode:  http.StatusMultiStatus,
+			expectedBody:  "{\"data\":{\"dry_run\":false,\"total\":4,\"valid\":1,\"created\":0,\"failed\":4,\"errors\":[{\"line\":2,\"errors\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\"},{\"line\":3,\"errors\":\"Conflicting value\"},{\"line\":4,\"errors\":\"'name' field is required\"},{\"line\":5,\"errors\":\"extraneous or missing \\\" in quoted-field\"}]}}",
+		},
+		{
+			name:          "on CSV body with quoted formulas",
+			importUsers:   NewImportMock(map[string]error{"'=1+1": &pgconn.PgError{Code: "23505"}, "''@me": &pgconn.PgError{Code: "23505"}}),
+			contentType:   "text/csv",
+			body:          bytes.NewReader([]byte("name\n'=1+1\n''@me\n'Ohara\n")),
+			expectedCalls: 1,
+			expectedCode:  http.StatusMultiStatus,
+			expectedBody:  "{\"data\":{\"dry_run\":false,\"total\":3,\"valid\":3,\"created\":1,\"failed\":2,\"errors\":[{\"line\":2,\"errors\":\"Conflicting value\"},{\"line\":3,\"errors\":\"Conflicting value\"}]}}",
+		},
+		{
+			name:          "on NDJSON dry run",
+			importUsers:   NewImportMock(nil),
+			query:         "?dry_run=true",
+			contentType:   "application/x-ndjson",
+			body:          bytes.NewReader([]byte("{\"name\":\"Jane\"}\n\n{\"name\":1}\n{\"name\":\"John\"}")),
+			expectedCalls: 0,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "{\"data\":{\"dry_run\":true,\"total\":3,\"valid\":2,\"created\":0,\"failed\":1,\"errors\":[{\"line\":3,\"errors\":\"'name' field must be of type string\"}]}}",
+		},
+		{
+			name:          "on multipart upload",
+			importUsers:   NewImportMock(nil),
+			contentType:   uploadType,
+			body:          upload,
+			expectedCalls: 1,
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "{\"data\":{\"dry_run\":false,\"total\":1,\"valid\":1,\"created\":1,\"failed\":0,\"errors\":[]}}",
+		},
+		{
+			name:         "on unknown format",
+			importUsers:  NewImportMock(nil),
+			contentType:  "text/plain",
+			body:         bytes.NewReader([]byte("name\nJane\n")),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"unable to tell the format of the file, set the 'format' field to 'csv' or 'ndjson'\",\"instance\":\"/import\"}",
+		},
+		{
+			name:         "on unknown CSV column",
+			importUsers:  NewImportMock(nil),
+			query:        "?format=csv",
+			contentType:  "text/plain",
+			body:         bytes.NewReader([]byte("name,age\nJane,33\n")),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"unknown column \\\"age\\\"\",\"instance\":\"/import\"}",
+		},
+		{
+			name:         "on missing name column",
+			importUsers:  NewImportMock(nil),
+			contentType:  "text/csv",
+			body:         bytes.NewReader([]byte("email\njane@test.com\n")),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"missing column \\\"name\\\"\",\"instance\":\"/import\"}",
+		},
+		{
+			name:         "on empty file",
+			importUsers:  NewImportMock(nil),
+			contentType:  "application/x-ndjson",
+			body:         bytes.NewReader([]byte("\n")),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"the file is empty\",\"instance\":\"/import\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/import"
+
+			actions := dependencies.Actions{Import: test.importUsers.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodPost, url+test.query, test.body)
+			request.Header.Set("Content-Type", test.contentType)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.POST(url, handler.Import)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertInt(t, test.importUsers.calls, test.expectedCalls)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"users/domain/entities"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+// change modifies the user of the given ID, expecting the given version.
+type change func(ctx context.Context, id string, version int64) (*entities.User, error)
+
+// modify is the part of PUT and PATCH that follows the parsing of the body: it checks If-Match,
+// applies the change and answers with the modified user and its new ETag.
+func (h *Handlers) modify(ctx *gin.Context, tracerCtx context.Context, span trace.Span, data []byte, apply change) {
+	headers := mapToString(ctx.Request.Header)
+
+	id := ctx.Param("id")
+
+	version, ok := checkIfMatch(ctx, span)
+	if !ok {
+		return
+	}
+
+	type result struct {
+		user *entities.User
+		err  error
+	}
+
+	resultChan := make(chan result, 1)
+	go func(id string, version int64) {
+		var r result
+		r.user, r.err = apply(tracerCtx, id, version)
+		resultChan <- r
+	}(id, version)
+
+	r := <-resultChan
+
+	if r.err != nil {
+		span.RecordError(r.err)
+		span.SetStatus(codes.Error, r.err.Error())
+		ctx.Error(r.err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.body", string(data)))
+	span.SetAttributes(attribute.String("http.path.id", id))
+
+	ctx.Header("ETag", responses.ETag(r.user))
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUser(r.user)})
+}
+
+// bindUpdateUser decodes the body already read into data, reporting values of the wrong type against
+// their field.
+func bindUpdateUser(ctx *gin.Context, data []byte) (requests.UpdateUser, error) {
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body requests.UpdateUser
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		return requests.UpdateUser{}, requests.FromDecodeError(err)
+	}
+
+	return body, nil
+}
+
+// bindError reports err as an invalid request payload.
+func bindError(ctx *gin.Context, span trace.Span, err error) *gin.Error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return ctx.Error(err).SetType(gin.ErrorTypeBind)
+}
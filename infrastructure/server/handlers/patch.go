@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"users/domain/entities"
+	"users/infrastructure/server/requests"
+)
+
+const (
+	mimeMergePatch = "application/merge-patch+json"
+	mimeJSONPatch  = "application/json-patch+json"
+)
+
+// Patch godoc
+// @Summary     Partially modify a user
+// @Description Applies either a JSON merge patch (RFC 7396), where missing fields are left unchanged and null clears a field,
+// @Description or a JSON Patch (RFC 6902) with 'test', 'replace' and 'remove' operations, applied atomically.
+// @Id          Patch
+// @Accept      application/merge-patch+json
+// @Accept      application/json-patch+json
+// @Produce     json
+// @Param       id path string true "The ID of the user."
+// @Param       request body requests.UpdateUser true "The fields to change, or a JSON Patch document."
+// @Param       If-Match header string false "Only update the user if its ETag matches. Weak tags never match and a list must name a single version."
+// @Success     200 {object} responses.UserResponse
+// @Header      200 {string} ETag "The version of the updated user."
+// @Failure     400 {object} handlers.Problem
+// @Failure     404 {object} handlers.Problem
+// @Failure     409 {object} handlers.Problem
+// @Failure     412 {object} handlers.Problem
+// @Failure     415 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/{id} [patch]
+func (h *Handlers) Patch(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Patch")
+	defer span.End()
+
+	contentType := ctx.ContentType()
+	if contentType != mimeMergePatch && contentType != mimeJSONPatch && contentType != gin.MIMEJSON {
+		err := fmt.Errorf("unsupported content type %q, use %q or %q", contentType, mimeMergePatch, mimeJSONPatch)
+		bindError(ctx, span, err).SetMeta(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	data, err := ctx.GetRawData()
+	if err != nil {
+		bindError(ctx, span, err)
+		return
+	}
+
+	if contentType == mimeJSONPatch {
+		ops, err := requests.ParseJSONPatch(data)
+		if err != nil {
+			bindError(ctx, span, err)
+			return
+		}
+
+		h.modify(ctx, tracerCtx, span, data, func(ctx context.Context, id string, version int64) (*entities.User, error) {
+			return h.actions.ApplyPatch(ctx, id, ops, version)
+		})
+		return
+	}
+
+	fields, err := mergePatchFields(ctx, data)
+	if err != nil {
+		bindError(ctx, span, err)
+		return
+	}
+
+	h.modify(ctx, tracerCtx, span, data, func(ctx context.Context, id string, version int64) (*entities.User, error) {
+		return h.actions.Update(ctx, id, fields, version)
+	})
+}
+
+func mergePatchFields(ctx *gin.Context, data []byte) (map[string]interface{}, error) {
+	body, err := bindUpdateUser(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := body.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/dependencies"
+)
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		update         *UpdateMock
+		contentType    string
+		body           io.Reader
+		ifMatch        string
+		expectedCode   int
+		expectedETag   string
+		expectedBody   string
+		expectedFields map[string]interface{}
+	}{
+		{
+			name:         "on OK execution",
+			update:       NewUpdateMock(&entities.User{Name: "test", Version: 2}, nil),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{"name":"test","email":null,"active":false}`)),
+			ifMatch:      "\"1\"",
+			expectedCode: http.StatusOK,
+			expectedETag: "\"2\"",
+			expectedBody: "{\"data\":{\"id\":\"\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}}",
+			expectedFields: map[string]interface{}{
+				"name":   "test",
+				"email":  nil,
+				"active": false,
+			},
+		},
+		{
+			name:         "on plain JSON",
+			update:       NewUpdateMock(&entities.User{Name: "test", Version: 2}, nil),
+			contentType:  gin.MIMEJSON,
+			body:         bytes.NewReader([]byte(`{"location":"Bogotá"}`)),
+			expectedCode: http.StatusOK,
+			expectedETag: "\"2\"",
+			expectedBody: "{\"data\":{\"id\":\"\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}}",
+			expectedFields: map[string]interface{}{
+				"location": "Bogotá",
+			},
+		},
+		{
+			name:         "on unsupported content type",
+			update:       NewUpdateMock(nil, nil),
+			contentType:  "text/plain",
+			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
+			expectedCode: http.StatusUnsupportedMediaType,
+			expectedBody: "{\"type\":\"urn:users-api:problem:unsupported-media-type\",\"title\":\"Unsupported media type\",\"status\":415,\"detail\":\"unsupported content type \\\"text/plain\\\", use \\\"application/merge-patch+json\\\" or \\\"application/json-patch+json\\\"\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on invalid email",
+			update:       NewUpdateMock(nil, nil),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{"name":"test","email":"test@"}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'email' field must be a valid email address, got \\\"test@\\\"\",\"instance\":\"/1\",\"errors\":[{\"field\":\"email\",\"code\":\"invalid_format\",\"message\":\"'email' field must be a valid email address, got \\\"test@\\\"\"}]}",
+		},
+		{
+			name:         "on null name",
+			update:       NewUpdateMock(nil, nil),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{"name":null}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'name' field cannot be null\",\"instance\":\"/1\",\"errors\":[{\"field\":\"name\",\"code\":\"not_null\",\"message\":\"'name' field cannot be null\"}]}",
+		},
+		{
+			name:         "on null active",
+			update:       NewUpdateMock(nil, nil),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{"active":null}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'active' field cannot be null\",\"instance\":\"/1\",\"errors\":[{\"field\":\"active\",\"code\":\"not_null\",\"message\":\"'active' field cannot be null\"}]}",
+		},
+		{
+			name:         "on empty patch",
+			update:       NewUpdateMock(nil, nil),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"at least one field is required\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on patch that is not an object",
+			update:       NewUpdateMock(nil, nil),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`["name"]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"the user must be a JSON object\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on version mismatch",
+			update:       NewUpdateMock(nil, errorspkg.AppVersionMismatch),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
+			ifMatch:      "\"1\"",
+			expectedCode: http.StatusPreconditionFailed,
+			expectedBody: "{\"type\":\"urn:users-api:problem:version-mismatch\",\"title\":\"Version mismatch\",\"status\":412,\"detail\":\"app: user has been modified by another request\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on repository error",
+			update:       NewUpdateMock(nil, errors.New("an error occurred")),
+			contentType:  mimeMergePatch,
+			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/1"
+
+			actions := dependencies.Actions{Update: test.update.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodPatch, url, test.body)
+			request.Header.Set("Content-Type", test.contentType)
+			request.Header.Set(ifMatch, test.ifMatch)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.PATCH(url, handler.Patch)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Header().Get("ETag"), test.expectedETag)
+			assertString(t, response.Body.String(), test.expectedBody)
+			if test.expectedFields != nil {
+				assert.Equal(t, test.expectedFields, test.update.fields)
+			}
+		})
+	}
+}
+
+type ApplyPatchMock struct {
+	execute func(context.Context, string, []entities.PatchOperation, int64) (*entities.User, error)
+	answer  *entities.User
+	err     error
+	ops     []entities.PatchOperation
+}
+
+func NewApplyPatchMock(answer *entities.User, err error) *ApplyPatchMock {
+	mock := &ApplyPatchMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, id string, ops []entities.PatchOperation, version int64) (*entities.User, error) {
+		mock.ops = ops
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestPatchWithJSONPatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		applyPatch   *ApplyPatchMock
+		body         io.Reader
+		expectedCode int
+		expectedBody string
+		expectedOps  []entities.PatchOperation
+	}{
+		{
+			name:         "on OK execution",
+			applyPatch:   NewApplyPatchMock(&entities.User{Name: "test", Version: 2}, nil),
+			body:         bytes.NewReader([]byte(`[{"op":"test","path":"/email","value":null},{"op":"replace","path":"/birth","value":"23/09/1992"},{"op":"replace","path":"/active","value":false},{"op":"remove","path":"/location"}]`)),
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":{\"id\":\"\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}}",
+			expectedOps: []entities.PatchOperation{
+				{Op: entities.PatchTest, Field: "email"},
+				{Op: entities.PatchReplace, Field: "birth", Value: time.Date(1992, 9, 23, 0, 0, 0, 0, time.UTC)},
+				{Op: entities.PatchReplace, Field: "active", Value: false},
+				{Op: entities.PatchRemove, Field: "location"},
+			},
+		},
+		{
+			name:         "on failed test operation",
+			applyPatch:   NewApplyPatchMock(nil, errorspkg.AppPatchTestFailed),
+			body:         bytes.NewReader([]byte(`[{"op":"test","path":"/name","value":"old"},{"op":"replace","path":"/name","value":"new"}]`)),
+			expectedCode: http.StatusConflict,
+			expectedBody: "{\"type\":\"urn:users-api:problem:patch-test-failed\",\"title\":\"Patch test failed\",\"status\":409,\"detail\":\"app: patch test operation failed\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on unsupported operation",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[{"op":"add","path":"/name","value":"new"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"operation 0: unsupported operation \\\"add\\\"\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on unknown path",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[{"op":"replace","path":"/id","value":"2"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"operation 0: unknown path \\\"/id\\\"\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on removing a required field",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[{"op":"remove","path":"/name"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"operation 0: \\\"/name\\\" cannot be removed\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on missing value",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[{"op":"replace","path":"/email"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"operation 0: missing 'value' for \\\"replace\\\" operation\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on invalid value",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[{"op":"replace","path":"/active","value":"yes"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"operation 0: 'active' field must be of type bool\",\"instance\":\"/1\",\"errors\":[{\"field\":\"active\",\"code\":\"invalid_type\",\"message\":\"'active' field must be of type bool\"}]}",
+		},
+		{
+			name:         "on operation of the wrong type",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[{"op":1,"path":"/name"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'0.op' field must be of type string\",\"instance\":\"/1\",\"errors\":[{\"field\":\"0.op\",\"code\":\"invalid_type\",\"message\":\"'0.op' field must be of type string\"}]}",
+		},
+		{
+			name:         "on empty document",
+			applyPatch:   NewApplyPatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"at least one operation is required\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on repository error",
+			applyPatch:   NewApplyPatchMock(nil, errors.New("an error occurred")),
+			body:         bytes.NewReader([]byte(`[{"op":"replace","path":"/name","value":"new"}]`)),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/1"
+
+			actions := dependencies.Actions{ApplyPatch: test.applyPatch.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodPatch, url, test.body)
+			request.Header.Set("Content-Type", mimeJSONPatch)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.PATCH(url, handler.Patch)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+			if test.expectedOps != nil {
+				assert.Equal(t, test.expectedOps, test.applyPatch.ops)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+)
+
+// Purge godoc
+// @Summary     Permanently delete a user from the trash. Requires the admin key.
+// @Id          Purge
+// @Produce     json
+// @Param       X-Admin-Key header string true "Admin key"
+// @Param       id path string true "User ID"
+// @Success     204
+// @Failure     403 {object} handlers.Problem
+// @Failure     404 {object} handlers.Problem
+// @Failure     409 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /admin/users/{id} [delete]
+func (h *Handlers) Purge(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Purge")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	id := ctx.Param("id")
+
+	chErr := make(chan error, 1)
+	go func(id string) {
+		chErr <- h.actions.Purge(tracerCtx, id)
+	}(id)
+
+	if err := <-chErr; err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.path.id", id))
+
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
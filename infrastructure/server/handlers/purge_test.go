@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/dependencies"
+)
+
+type PurgeMock struct {
+	execute func(context.Context, string) error
+	err     error
+}
+
+func NewPurgeMock(err error) *PurgeMock {
+	mock := &PurgeMock{
+		err: err,
+	}
+
+	mock.execute = func(ctx context.Context, id string) error {
+		return err
+	}
+
+	return mock
+}
+
+func TestPurge(t *testing.T) {
+	tests := []struct {
+		name         string
+		purge        *PurgeMock
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "on OK execution",
+			purge:        NewPurgeMock(nil),
+			expectedCode: http.StatusNoContent,
+			expectedBody: "",
+		},
+		{
+			name:         "on user not in the trash",
+			purge:        NewPurgeMock(errorspkg.AppUserNotDeleted),
+			expectedCode: http.StatusConflict,
+			expectedBody: "{\"type\":\"urn:users-api:problem:user-not-deleted\",\"title\":\"User not in the trash\",\"status\":409,\"detail\":\"app: user must be in the trash before it is purged\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on repository error",
+			purge:        NewPurgeMock(errors.New("an error occurred")),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/1"
+
+			actions := dependencies.Actions{Purge: test.purge.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodDelete, url, nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.DELETE(url, handler.Purge)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
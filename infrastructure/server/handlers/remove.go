@@ -8,14 +8,18 @@ import (
 )
 
 // Remove godoc
-// @Summary     Delete a user.
+// @Summary     Move a user to the trash.
 // @Id          Remove
 // @Accept      json
 // @Produce     json
 // @Param       id path string true "User ID"
+// @Param       If-Match header string false "Only delete the user if its ETag matches. Weak tags never match and a list must name a single version."
 // @Success     204
-// @Failure     400 {object} error "error"
-// @Failure     500 {object} error "error"
+// @Failure     400 {object} handlers.Problem
+// @Failure     404 {object} handlers.Problem
+// @Failure     412 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
 // @Router      /users/{id} [delete]
 func (h *Handlers) Remove(ctx *gin.Context) {
 	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Remove")
@@ -25,15 +29,20 @@ func (h *Handlers) Remove(ctx *gin.Context) {
 
 	id := ctx.Param("id")
 
+	version, ok := checkIfMatch(ctx, span)
+	if !ok {
+		return
+	}
+
 	chErr := make(chan error, 1)
-	go func(id string) {
-		chErr <- h.actions.Remove(tracerCtx, id)
-	}(id)
+	go func(id string, version int64) {
+		chErr <- h.actions.Remove(tracerCtx, id, version)
+	}(id, version)
 
 	if err := <-chErr; err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
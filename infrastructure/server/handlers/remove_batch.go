@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"io"
+	"net/http"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+// RemoveBatch godoc
+// @Summary     Move several users to the trash
+// @Description Every listed user is removed in a single transaction.
+// @Id          RemoveBatch
+// @Accept      json
+// @Produce     json
+// @Param       request body requests.RemoveBatch true "The IDs of the users."
+// @Success     200 {object} responses.BatchRemoveResponse
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/batch [delete]
+func (h *Handlers) RemoveBatch(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-RemoveBatch")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	data, err := ctx.GetRawData()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body requests.RemoveBatch
+	if err = ctx.ShouldBindJSON(&body); err != nil {
+		bindError(ctx, span, requests.FromBindError(err, &body))
+		return
+	}
+
+	result, err := h.actions.RemoveBatch(tracerCtx, body.IDs)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.body", string(data)))
+	span.SetAttributes(attribute.Int("http.response.batch.removed", len(result.Users)))
+	span.SetAttributes(attribute.Int("http.response.batch.not_found", len(result.NotFound)))
+
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromBatchRemove(result)})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	"users/infrastructure/dependencies"
+)
+
+type RemoveBatchMock struct {
+	execute func(context.Context, []string) (*entities.BatchChange, error)
+	answer  *entities.BatchChange
+	err     error
+}
+
+func NewRemoveBatchMock(answer *entities.BatchChange, err error) *RemoveBatchMock {
+	mock := &RemoveBatchMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, ids []string) (*entities.BatchChange, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestRemoveBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		removeBatch  *RemoveBatchMock
+		body         io.Reader
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "on OK execution",
+			removeBatch: NewRemoveBatchMock(&entities.BatchChange{
+				Users:    []*entities.User{{ID: "1"}},
+				NotFound: []string{"2"},
+			}, nil),
+			body:         bytes.NewReader([]byte(`{"ids":["1","2"]}`)),
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":{\"removed\":[\"1\"],\"not_found\":[\"2\"]}}",
+		},
+		{
+			name:         "on empty ids",
+			removeBatch:  NewRemoveBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"ids":[]}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'ids' field must contain at least 1 item\",\"instance\":\"/batch\",\"errors\":[{\"field\":\"ids\",\"code\":\"too_short\",\"message\":\"'ids' field must contain at least 1 item\"}]}",
+		},
+		{
+			name:         "on blank id",
+			removeBatch:  NewRemoveBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"ids":["1",""]}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'ids.1' field is required\",\"instance\":\"/batch\",\"errors\":[{\"field\":\"ids.1\",\"code\":\"required\",\"message\":\"'ids.1' field is required\"}]}",
+		},
+		{
+			name:         "on repository error",
+			removeBatch:  NewRemoveBatchMock(nil, errors.New("an error occurred")),
+			body:         bytes.NewReader([]byte(`{"ids":["1"]}`)),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/batch\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/batch"
+
+			actions := dependencies.Actions{RemoveBatch: test.removeBatch.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodDelete, url, test.body)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.DELETE(url, handler.RemoveBatch)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
@@ -7,11 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	errorspkg "users/domain/errors"
 	"users/infrastructure/dependencies"
 )
 
 type RemoveMock struct {
-	execute func(context.Context, string) error
+	execute func(context.Context, string, int64) error
 	err     error
 }
 
@@ -20,7 +21,7 @@ func NewRemoveMock(err error) *RemoveMock {
 		err: err,
 	}
 
-	mock.execute = func(ctx context.Context, id string) error {
+	mock.execute = func(ctx context.Context, id string, version int64) error {
 		return err
 	}
 
@@ -31,6 +32,7 @@ func TestRemove(t *testing.T) {
 	tests := []struct {
 		name         string
 		remove       *RemoveMock
+		ifMatch      string
 		expectedCode int
 		expectedBody string
 	}{
@@ -40,11 +42,32 @@ func TestRemove(t *testing.T) {
 			expectedCode: http.StatusNoContent,
 			expectedBody: "",
 		},
+		{
+			name:         "on invalid If-Match",
+			remove:       NewRemoveMock(nil),
+			ifMatch:      "W/\"1\"",
+			expectedCode: http.StatusPreconditionFailed,
+			expectedBody: "{\"type\":\"urn:users-api:problem:version-mismatch\",\"title\":\"Version mismatch\",\"status\":412,\"detail\":\"app: user has been modified by another request\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on If-Match with several versions",
+			remove:       NewRemoveMock(nil),
+			ifMatch:      "\"1\", \"2\"",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"the If-Match header lists 2 versions of the user, it must list a single entity tag\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on version mismatch",
+			remove:       NewRemoveMock(errorspkg.AppVersionMismatch),
+			ifMatch:      "\"1\"",
+			expectedCode: http.StatusPreconditionFailed,
+			expectedBody: "{\"type\":\"urn:users-api:problem:version-mismatch\",\"title\":\"Version mismatch\",\"status\":412,\"detail\":\"app: user has been modified by another request\",\"instance\":\"/1\"}",
+		},
 		{
 			name:         "on repository error",
 			remove:       NewRemoveMock(errors.New("an error occurred")),
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: "{\"errors\":\"an error occurred\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\"}",
 		},
 	}
 
@@ -56,9 +79,11 @@ func TestRemove(t *testing.T) {
 			handler := New(&actions)
 
 			request, _ := http.NewRequest(http.MethodDelete, url, nil)
+			request.Header.Set(ifMatch, test.ifMatch)
 			response := httptest.NewRecorder()
 
 			router := gin.New()
+			router.Use(Errors())
 			router.DELETE(url, handler.Remove)
 			router.ServeHTTP(response, request)
 
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+	"users/domain/entities"
+	"users/infrastructure/server/responses"
+)
+
+// Restore godoc
+// @Summary     Restore a deleted user
+// @Id          Restore
+// @Produce     json
+// @Param       id path string true "User ID"
+// @Success     200 {object} responses.UserResponse
+// @Header      200 {string} ETag "The version of the restored user."
+// @Failure     404 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/{id}/restore [post]
+func (h *Handlers) Restore(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Restore")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	id := ctx.Param("id")
+
+	type result struct {
+		user *entities.User
+		err  error
+	}
+
+	resultChan := make(chan result, 1)
+	go func(id string) {
+		var r result
+		r.user, r.err = h.actions.Restore(tracerCtx, id)
+		resultChan <- r
+	}(id)
+
+	r := <-resultChan
+
+	if r.err != nil {
+		span.RecordError(r.err)
+		span.SetStatus(codes.Error, r.err.Error())
+		ctx.Error(r.err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.path.id", id))
+
+	ctx.Header("ETag", responses.ETag(r.user))
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUser(r.user)})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	"users/infrastructure/dependencies"
+)
+
+type RestoreMock struct {
+	execute func(context.Context, string) (*entities.User, error)
+	answer  *entities.User
+	err     error
+}
+
+func NewRestoreMock(answer *entities.User, err error) *RestoreMock {
+	mock := &RestoreMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, id string) (*entities.User, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name         string
+		restore      *RestoreMock
+		expectedCode int
+		expectedETag string
+		expectedBody string
+	}{
+		{
+			name:         "on OK execution",
+			restore:      NewRestoreMock(&entities.User{ID: "1", Version: 3}, nil),
+			expectedCode: http.StatusOK,
+			expectedETag: "\"3\"",
+			expectedBody: "{\"data\":{\"id\":\"1\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"3\\\"\"}}",
+		},
+		{
+			name:         "on repository error",
+			restore:      NewRestoreMock(nil, errors.New("an error occurred")),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1/restore\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/1/restore"
+
+			actions := dependencies.Actions{Restore: test.restore.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodPost, url, nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.POST(url, handler.Restore)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Header().Get("ETag"), test.expectedETag)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
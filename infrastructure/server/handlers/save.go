@@ -19,8 +19,11 @@ import (
 // @Produce     json
 // @Param       payload body requests.SaveUser true "Create a user: 'name' field is required; all other fields are optional."
 // @Success     201 {object} responses.UserResponse
-// @Failure     400 {object} error "error"
-// @Failure     500 {object} error "error"
+// @Header      201 {string} ETag "The version of the created user."
+// @Failure     400 {object} handlers.Problem
+// @Failure     409 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
 // @Router      /users [post]
 func (h *Handlers) Save(ctx *gin.Context) {
 	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Save")
@@ -32,7 +35,7 @@ func (h *Handlers) Save(ctx *gin.Context) {
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": err.Error()})
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -42,7 +45,7 @@ func (h *Handlers) Save(ctx *gin.Context) {
 	if bindErr := ctx.ShouldBindJSON(&body); bindErr != nil {
 		span.RecordError(bindErr)
 		span.SetStatus(codes.Error, bindErr.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": bindErr.Error()})
+		ctx.Error(requests.FromDecodeError(bindErr)).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -50,7 +53,7 @@ func (h *Handlers) Save(ctx *gin.Context) {
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{"errors": err.Error()})
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -71,7 +74,7 @@ func (h *Handlers) Save(ctx *gin.Context) {
 	if r.err != nil {
 		span.RecordError(r.err)
 		span.SetStatus(codes.Error, r.err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": r.err.Error()})
+		ctx.Error(r.err)
 		return
 	}
 
@@ -79,5 +82,6 @@ func (h *Handlers) Save(ctx *gin.Context) {
 	span.SetAttributes(attribute.String("http.headers", headers))
 	span.SetAttributes(attribute.String("http.body", string(data)))
 
+	ctx.Header("ETag", responses.ETag(r.user))
 	ctx.JSON(http.StatusCreated, gin.H{"data": responses.FromUser(r.user)})
 }
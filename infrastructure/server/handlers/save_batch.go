@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+// SaveBatch godoc
+// @Summary     Create several users
+// @Description Every item is reported with its own status. Without 'atomic' the valid items are created even if others fail;
+// @Description with 'atomic=true' nothing is created unless every item is.
+// @Id          SaveBatch
+// @Accept      json
+// @Produce     json
+// @Param       payload body []requests.SaveUser true "Users to create, with the same fields as a single creation."
+// @Param       atomic query bool false "Roll back the whole batch if any item fails."
+// @Success     201 {array} responses.BatchItemResponse
+// @Success     207 {array} responses.BatchItemResponse
+// @Failure     400 {object} handlers.Problem
+// @Failure     422 {array} responses.BatchItemResponse
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/batch [post]
+func (h *Handlers) SaveBatch(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-SaveBatch")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	var query requests.SaveBatch
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	data, err := ctx.GetRawData()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	users, errs, err := requests.ParseSaveBatch(data)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result := make([]*responses.BatchItemResponse, len(users))
+
+	var valid []*entities.User
+	var positions []int
+	for i, user := range users {
+		if errs[i] != nil {
+			result[i] = batchItemError(i, errs[i])
+			continue
+		}
+		valid = append(valid, user)
+		positions = append(positions, i)
+	}
+
+	if query.Atomic && len(valid) < len(users) {
+		for _, i := range positions {
+			result[i] = batchItemError(i, errorspkg.AppBatchAborted)
+		}
+		span.SetStatus(codes.Error, errorspkg.AppBatchAborted.Error())
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"data": result})
+		return
+	}
+
+	saved, err := h.actions.SaveBatch(tracerCtx, valid, query.Atomic)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	created := 0
+	for j, r := range saved {
+		i := positions[j]
+		if r.Err != nil {
+			result[i] = batchItemError(i, r.Err)
+			continue
+		}
+		result[i] = responses.FromBatchItem(i, http.StatusCreated, r.User)
+		created++
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.Bool("http.query.atomic", query.Atomic))
+	span.SetAttributes(attribute.Int("http.response.batch.created", created))
+
+	switch {
+	case created == len(users):
+		ctx.JSON(http.StatusCreated, gin.H{"data": result})
+	case query.Atomic:
+		span.SetStatus(codes.Error, errorspkg.AppBatchAborted.Error())
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"data": result})
+	default:
+		ctx.JSON(http.StatusMultiStatus, gin.H{"data": result})
+	}
+}
+
+// batchItemError reports the failure of an item as Errors would report it for a single creation.
+func batchItemError(index int, err error) *responses.BatchItemResponse {
+	if fieldErrs := requests.FieldErrors(err); fieldErrs != nil {
+		return responses.FromBatchItemError(index, http.StatusBadRequest, err.Error(), fieldErrs)
+	}
+
+	status, detail := describeError(err)
+	return responses.FromBatchItemError(index, status, detail, nil)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	errorspkg "users/domain/errors"
+	"users/infrastructure/dependencies"
+)
+
+type SaveBatchMock struct {
+	execute func(context.Context, []*entities.User, bool) ([]*entities.BatchResult, error)
+	answer  []*entities.BatchResult
+	err     error
+}
+
+func NewSaveBatchMock(answer []*entities.BatchResult, err error) *SaveBatchMock {
+	mock := &SaveBatchMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, users []*entities.User, atomic bool) ([]*entities.BatchResult, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestSaveBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		saveBatch    *SaveBatchMock
+		query        string
+		body         io.Reader
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "on OK execution",
+			saveBatch: NewSaveBatchMock([]*entities.BatchResult{
+				{User: &entities.User{ID: "1", Name: "a"}},
+				{User: &entities.User{ID: "2", Name: "b"}},
+			}, nil),
+			body:         bytes.NewReader([]byte(`[{"name":"a"},{"name":"b"}]`)),
+			expectedCode: http.StatusCreated,
+			expectedBody: "{\"data\":[{\"index\":0,\"status\":201,\"data\":{\"id\":\"1\",\"name\":\"a\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}},{\"index\":1,\"status\":201,\"data\":{\"id\":\"2\",\"name\":\"b\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}}]}",
+		},
+		{
+			name: "on invalid item",
+			saveBatch: NewSaveBatchMock([]*entities.BatchResult{
+				{User: &entities.User{ID: "2", Name: "b"}},
+			}, nil),
+			body:         bytes.NewReader([]byte(`[{"email":"a@test.com"},{"name":"b"}]`)),
+			expectedCode: http.StatusMultiStatus,
+			expectedBody: "{\"data\":[{\"index\":0,\"status\":400,\"detail\":\"'name' field is required\",\"errors\":[{\"field\":\"name\",\"code\":\"required\",\"message\":\"'name' field is required\"}]},{\"index\":1,\"status\":201,\"data\":{\"id\":\"2\",\"name\":\"b\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}}]}",
+		},
+		{
+			name: "on items of the wrong type",
+			saveBatch: NewSaveBatchMock([]*entities.BatchResult{
+				{User: &entities.User{ID: "3", Name: "c"}},
+			}, nil),
+			body:         bytes.NewReader([]byte(`[{"name":1},"b",{"name":"c"}]`)),
+			expectedCode: http.StatusMultiStatus,
+			expectedBody: "{\"data\":[{\"index\":0,\"status\":400,\"detail\":\"'name' field must be of type string\",\"errors\":[{\"field\":\"name\",\"code\":\"invalid_type\",\"message\":\"'name' field must be of type string\"}]},{\"index\":1,\"status\":400,\"detail\":\"item must be a JSON object\",\"errors\":[{\"field\":\"\",\"code\":\"invalid_type\",\"message\":\"item must be a JSON object\"}]},{\"index\":2,\"status\":201,\"data\":{\"id\":\"3\",\"name\":\"c\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}}]}",
+		},
+		{
+			name:         "on invalid item in atomic mode",
+			saveBatch:    NewSaveBatchMock(nil, errors.New("must not be called")),
+			query:        "?atomic=true",
+			body:         bytes.NewReader([]byte(`[{"name":"a"},{"name":"b","birth":"23/09/92"}]`)),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "{\"data\":[{\"index\":0,\"status\":424,\"detail\":\"app: batch rolled back because another item failed\"},{\"index\":1,\"status\":400,\"detail\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\",\"errors\":[{\"field\":\"birth\",\"code\":\"invalid_format\",\"message\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\"}]}]}",
+		},
+		{
+			name: "on failed item in atomic mode",
+			saveBatch: NewSaveBatchMock([]*entities.BatchResult{
+				{Err: &pgconn.PgError{Code: "23505", Detail: "Key (email)=(jim@test.com) already exists."}},
+				{Err: errorspkg.AppBatchAborted},
+			}, nil),
+			query:        "?atomic=true",
+			body:         bytes.NewReader([]byte(`[{"name":"a"},{"name":"b"}]`)),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "{\"data\":[{\"index\":0,\"status\":409,\"detail\":\"Conflicting value\"},{\"index\":1,\"status\":424,\"detail\":\"app: batch rolled back because another item failed\"}]}",
+		},
+		{
+			name:         "on empty batch",
+			saveBatch:    NewSaveBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`[]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"batch must contain at least one user\",\"instance\":\"/batch\"}",
+		},
+		{
+			name:         "on payload that is not an array",
+			saveBatch:    NewSaveBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"name":"a"}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"batch must be a JSON array of users\",\"instance\":\"/batch\"}",
+		},
+		{
+			name:         "on invalid atomic flag",
+			saveBatch:    NewSaveBatchMock(nil, nil),
+			query:        "?atomic=maybe",
+			body:         bytes.NewReader([]byte(`[{"name":"a"}]`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"strconv.ParseBool: parsing \\\"maybe\\\": invalid syntax\",\"instance\":\"/batch\"}",
+		},
+		{
+			name:         "on repository error",
+			saveBatch:    NewSaveBatchMock(nil, errors.New("an error occurred")),
+			body:         bytes.NewReader([]byte(`[{"name":"a"}]`)),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/batch\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/batch"
+
+			actions := dependencies.Actions{SaveBatch: test.saveBatch.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodPost, url+test.query, test.body)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.POST(url, handler.SaveBatch)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
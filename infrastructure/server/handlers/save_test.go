@@ -8,8 +8,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"users/domain/entities"
+	errorspkg "users/domain/errors"
 	"users/infrastructure/dependencies"
 )
 
@@ -48,35 +50,57 @@ func TestSave(t *testing.T) {
 			save:         NewSaveMock(&entities.User{ID: "2"}, nil),
 			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
 			expectedCode: http.StatusCreated,
-			expectedBody: "{\"data\":{\"id\":\"2\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false}}",
+			expectedBody: "{\"data\":{\"id\":\"2\",\"name\":\"\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}}",
 		},
 		{
 			name:         "on nil payload",
 			save:         NewSaveMock(&entities.User{ID: "2"}, nil),
 			body:         nil,
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"cannot read nil body\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"cannot read nil body\",\"instance\":\"/\"}",
 		},
 		{
 			name:         "on missing name field",
 			save:         NewSaveMock(&entities.User{ID: "2"}, nil),
 			body:         bytes.NewReader([]byte(`{"email":"test@test.com"}`)),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"Key: 'SaveUser.Name' Error:Field validation for 'Name' failed on the 'required' tag\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'name' field is required\",\"instance\":\"/\",\"errors\":[{\"field\":\"name\",\"code\":\"required\",\"message\":\"'name' field is required\"}]}",
 		},
 		{
 			name:         "on invalid birth",
 			save:         NewSaveMock(&entities.User{ID: "2"}, nil),
 			body:         bytes.NewReader([]byte(`{"name":"test","birth":"23/09/92"}`)),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\",\"instance\":\"/\",\"errors\":[{\"field\":\"birth\",\"code\":\"invalid_format\",\"message\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\"}]}",
+		},
+		{
+			name: "on invalid fields",
+			save: NewSaveMock(&entities.User{ID: "2"}, nil),
+			body: bytes.NewReader([]byte(`{"name":"` + strings.Repeat("a", 101) + `","birth":"23/09/2999","email":"test.com","location":"` +
+				strings.Repeat("a", 256) + `"}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'name' field must not be longer than 100 characters; 'birth' field cannot be in the future, got \\\"23/09/2999\\\"; 'email' field must be a valid email address, got \\\"test.com\\\"; 'location' field must not be longer than 255 characters\",\"instance\":\"/\",\"errors\":[{\"field\":\"name\",\"code\":\"too_long\",\"message\":\"'name' field must not be longer than 100 characters\"},{\"field\":\"birth\",\"code\":\"in_future\",\"message\":\"'birth' field cannot be in the future, got \\\"23/09/2999\\\"\"},{\"field\":\"email\",\"code\":\"invalid_format\",\"message\":\"'email' field must be a valid email address, got \\\"test.com\\\"\"},{\"field\":\"location\",\"code\":\"too_long\",\"message\":\"'location' field must not be longer than 255 characters\"}]}",
+		},
+		{
+			name:         "on invalid type",
+			save:         NewSaveMock(&entities.User{ID: "2"}, nil),
+			body:         bytes.NewReader([]byte(`{"name":1}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'name' field must be of type string\",\"instance\":\"/\",\"errors\":[{\"field\":\"name\",\"code\":\"invalid_type\",\"message\":\"'name' field must be of type string\"}]}",
+		},
+		{
+			name:         "on email taken",
+			save:         NewSaveMock(nil, errorspkg.AppEmailTaken),
+			body:         bytes.NewReader([]byte(`{"name":"test","email":"Test@test.com"}`)),
+			expectedCode: http.StatusConflict,
+			expectedBody: "{\"type\":\"urn:users-api:problem:email-taken\",\"title\":\"Email already taken\",\"status\":409,\"detail\":\"app: email is already taken by another user\",\"instance\":\"/\"}",
 		},
 		{
 			name:         "on save repository error",
 			save:         NewSaveMock(nil, errors.New("an error occurred")),
 			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: "{\"errors\":\"an error occurred\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/\"}",
 		},
 	}
 
@@ -91,6 +115,7 @@ func TestSave(t *testing.T) {
 			response := httptest.NewRecorder()
 
 			router := gin.New()
+			router.Use(Errors())
 			router.POST(url, handler.Save)
 			router.ServeHTTP(response, request)
 
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"net/http"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+// Search godoc
+// @Summary     Search users by name, email or location
+// @Id          Search
+// @Produce     json
+// @Param       q query string true "Text to look for; partial and misspelled values are accepted."
+// @Param       limit query int false "Maximum number of users to return."
+// @Success     200 {array} responses.UserResponse
+// @Failure     400 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/search [get]
+func (h *Handlers) Search(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-Search")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	var query requests.SearchUsers
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.actions.Search(tracerCtx, query.Query, query.Limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.query.q", query.Query))
+
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromUserList(result)})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	"users/infrastructure/dependencies"
+)
+
+type SearchMock struct {
+	execute func(context.Context, string, int) ([]*entities.User, error)
+	answer  []*entities.User
+	err     error
+}
+
+func NewSearchMock(answer []*entities.User, err error) *SearchMock {
+	mock := &SearchMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, query string, limit int) ([]*entities.User, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name         string
+		search       *SearchMock
+		query        string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "on OK execution",
+			search:       NewSearchMock([]*entities.User{{ID: "1", Name: "jerson"}}, nil),
+			query:        "?q=jersn",
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":[{\"id\":\"1\",\"name\":\"jerson\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}]}",
+		},
+		{
+			name:         "on missing query",
+			search:       NewSearchMock(nil, nil),
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"Key: 'SearchUsers.Query' Error:Field validation for 'Query' failed on the 'required' tag\",\"instance\":\"/search\"}",
+		},
+		{
+			name:         "on repository error",
+			search:       NewSearchMock(nil, errors.New("an error occurred")),
+			query:        "?q=jersn",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/search\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/search"
+
+			actions := dependencies.Actions{Search: test.search.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodGet, url+test.query, nil)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.GET(url, handler.Search)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
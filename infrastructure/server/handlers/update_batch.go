@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"io"
+	"net/http"
+	"users/infrastructure/server/requests"
+	"users/infrastructure/server/responses"
+)
+
+// UpdateBatch godoc
+// @Summary     Modify several users at once
+// @Description The same fields are applied to every listed user in a single transaction.
+// @Id          UpdateBatch
+// @Accept      json
+// @Produce     json
+// @Param       request body requests.UpdateBatch true "The IDs of the users and the info to update."
+// @Success     200 {object} responses.BatchUpdateResponse
+// @Failure     400 {object} handlers.Problem
+// @Failure     409 {object} handlers.Problem
+// @Failure     500 {object} handlers.Problem
+// @Failure     504 {object} handlers.Problem
+// @Router      /users/batch [patch]
+func (h *Handlers) UpdateBatch(ctx *gin.Context) {
+	tracerCtx, span := h.tracer.Start(ctx.Request.Context(), "Handler-UpdateBatch")
+	defer span.End()
+
+	headers := mapToString(ctx.Request.Header)
+
+	data, err := ctx.GetRawData()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body requests.UpdateBatch
+	if err = ctx.ShouldBindJSON(&body); err != nil {
+		bindError(ctx, span, requests.FromBindError(err, &body))
+		return
+	}
+
+	fields, err := body.Fields.ToMap()
+	if err != nil {
+		bindError(ctx, span, err)
+		return
+	}
+
+	if len(fields) == 0 {
+		bindError(ctx, span, requests.ValidationErrors{{
+			Field:   "fields",
+			Code:    requests.CodeRequired,
+			Message: "at least one field is required",
+		}})
+		return
+	}
+
+	result, err := h.actions.UpdateBatch(tracerCtx, body.IDs, fields)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.String(xAppID, ctx.Request.Header.Get(xAppID)))
+	span.SetAttributes(attribute.String("http.headers", headers))
+	span.SetAttributes(attribute.String("http.body", string(data)))
+	span.SetAttributes(attribute.Int("http.response.batch.updated", len(result.Users)))
+	span.SetAttributes(attribute.Int("http.response.batch.not_found", len(result.NotFound)))
+
+	ctx.JSON(http.StatusOK, gin.H{"data": responses.FromBatchUpdate(result)})
+}
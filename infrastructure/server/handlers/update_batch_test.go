@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users/domain/entities"
+	"users/infrastructure/dependencies"
+)
+
+type UpdateBatchMock struct {
+	execute func(context.Context, []string, map[string]interface{}) (*entities.BatchChange, error)
+	answer  *entities.BatchChange
+	err     error
+}
+
+func NewUpdateBatchMock(answer *entities.BatchChange, err error) *UpdateBatchMock {
+	mock := &UpdateBatchMock{
+		answer: answer,
+		err:    err,
+	}
+
+	mock.execute = func(ctx context.Context, ids []string, fields map[string]interface{}) (*entities.BatchChange, error) {
+		if err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	return mock
+}
+
+func TestUpdateBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		updateBatch  *UpdateBatchMock
+		body         io.Reader
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "on OK execution",
+			updateBatch: NewUpdateBatchMock(&entities.BatchChange{
+				Users:    []*entities.User{{ID: "1", Name: "test"}},
+				NotFound: []string{"2"},
+			}, nil),
+			body:         bytes.NewReader([]byte(`{"ids":["1","2"],"fields":{"name":"test"}}`)),
+			expectedCode: http.StatusOK,
+			expectedBody: "{\"data\":{\"updated\":[{\"id\":\"1\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"0\\\"\"}],\"not_found\":[\"2\"]}}",
+		},
+		{
+			name:         "on missing ids",
+			updateBatch:  NewUpdateBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"fields":{"name":"test"}}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'ids' field is required\",\"instance\":\"/batch\",\"errors\":[{\"field\":\"ids\",\"code\":\"required\",\"message\":\"'ids' field is required\"}]}",
+		},
+		{
+			name:         "on missing fields",
+			updateBatch:  NewUpdateBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"ids":["1"]}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'fields' field is required\",\"instance\":\"/batch\",\"errors\":[{\"field\":\"fields\",\"code\":\"required\",\"message\":\"'fields' field is required\"}]}",
+		},
+		{
+			name:         "on empty fields",
+			updateBatch:  NewUpdateBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"ids":["1"],"fields":{}}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"at least one field is required\",\"instance\":\"/batch\",\"errors\":[{\"field\":\"fields\",\"code\":\"required\",\"message\":\"at least one field is required\"}]}",
+		},
+		{
+			name:         "on invalid field",
+			updateBatch:  NewUpdateBatchMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"ids":["1"],"fields":{"active":"maybe"}}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'active' field must be of type bool\",\"instance\":\"/batch\",\"errors\":[{\"field\":\"active\",\"code\":\"invalid_type\",\"message\":\"'active' field must be of type bool\"}]}",
+		},
+		{
+			name:         "on repository error",
+			updateBatch:  NewUpdateBatchMock(nil, errors.New("an error occurred")),
+			body:         bytes.NewReader([]byte(`{"ids":["1"],"fields":{"name":"test"}}`)),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/batch\"}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := "/batch"
+
+			actions := dependencies.Actions{UpdateBatch: test.updateBatch.execute}
+			handler := New(&actions)
+
+			request, _ := http.NewRequest(http.MethodPatch, url, test.body)
+			response := httptest.NewRecorder()
+
+			router := gin.New()
+			router.Use(Errors())
+			router.PATCH(url, handler.UpdateBatch)
+			router.ServeHTTP(response, request)
+
+			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Body.String(), test.expectedBody)
+		})
+	}
+}
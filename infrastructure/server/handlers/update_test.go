@@ -5,18 +5,22 @@ import (
 	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"users/domain/entities"
+	errorspkg "users/domain/errors"
 	"users/infrastructure/dependencies"
 )
 
 type UpdateMock struct {
-	execute func(context.Context, string, map[string]interface{}) (*entities.User, error)
+	execute func(context.Context, string, map[string]interface{}, int64) (*entities.User, error)
 	answer  *entities.User
 	err     error
+	fields  map[string]interface{}
 }
 
 func NewUpdateMock(answer *entities.User, err error) *UpdateMock {
@@ -25,7 +29,8 @@ func NewUpdateMock(answer *entities.User, err error) *UpdateMock {
 		err:    err,
 	}
 
-	mock.execute = func(ctx context.Context, id string, fields map[string]interface{}) (*entities.User, error) {
+	mock.execute = func(ctx context.Context, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
+		mock.fields = fields
 		if err != nil {
 			return nil, err
 		}
@@ -37,46 +42,103 @@ func NewUpdateMock(answer *entities.User, err error) *UpdateMock {
 
 func TestUpdate(t *testing.T) {
 	tests := []struct {
-		name         string
-		update       *UpdateMock
-		body         io.Reader
-		expectedCode int
-		expectedBody string
+		name           string
+		update         *UpdateMock
+		body           io.Reader
+		ifMatch        string
+		expectedCode   int
+		expectedETag   string
+		expectedBody   string
+		expectedFields map[string]interface{}
 	}{
 		{
 			name:         "on OK execution",
-			update:       NewUpdateMock(&entities.User{Name: "test"}, nil),
+			update:       NewUpdateMock(&entities.User{Name: "test", Version: 2}, nil),
 			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
+			ifMatch:      "\"1\"",
 			expectedCode: http.StatusOK,
-			expectedBody: "{\"data\":{\"id\":\"\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false}}",
+			expectedETag: "\"2\"",
+			expectedBody: "{\"data\":{\"id\":\"\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}}",
+			expectedFields: map[string]interface{}{
+				"name":     "test",
+				"birth":    nil,
+				"email":    nil,
+				"location": nil,
+				"active":   true,
+			},
+		},
+		{
+			name:         "on full replacement",
+			update:       NewUpdateMock(&entities.User{Name: "test", Version: 2}, nil),
+			body:         bytes.NewReader([]byte(`{"name":"test","birth":"23/09/1992","email":"test@test.com","location":null,"active":false}`)),
+			expectedCode: http.StatusOK,
+			expectedETag: "\"2\"",
+			expectedBody: "{\"data\":{\"id\":\"\",\"name\":\"test\",\"birth\":\"\",\"email\":\"\",\"location\":null,\"created_at\":\"0001-01-01 00:00:00\",\"updated_at\":\"0001-01-01 00:00:00\",\"active\":false,\"etag\":\"\\\"2\\\"\"}}",
+			expectedFields: map[string]interface{}{
+				"name":     "test",
+				"birth":    time.Date(1992, 9, 23, 0, 0, 0, 0, time.UTC),
+				"email":    "test@test.com",
+				"location": nil,
+				"active":   false,
+			},
+		},
+		{
+			name:         "on missing name",
+			update:       NewUpdateMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"email":"test@test.com"}`)),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'name' field is required\",\"instance\":\"/1\",\"errors\":[{\"field\":\"name\",\"code\":\"required\",\"message\":\"'name' field is required\"}]}",
 		},
 		{
 			name:         "on invalid type",
 			update:       NewUpdateMock(nil, nil),
 			body:         bytes.NewReader([]byte(`{"name":1}`)),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"json: cannot unmarshal number into Go struct field UpdateUser.name of type string\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"'name' field must be of type string\",\"instance\":\"/1\",\"errors\":[{\"field\":\"name\",\"code\":\"invalid_type\",\"message\":\"'name' field must be of type string\"}]}",
 		},
 		{
 			name:         "on nil payload",
 			update:       NewUpdateMock(nil, nil),
 			body:         nil,
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"cannot read nil body\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"cannot read nil body\",\"instance\":\"/1\"}",
 		},
 		{
 			name:         "on invalid birth",
 			update:       NewUpdateMock(nil, nil),
 			body:         bytes.NewReader([]byte(`{"name":"test","birth":"23/09/92"}`)),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"errors\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:invalid-request\",\"title\":\"Invalid request\",\"status\":400,\"detail\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\",\"instance\":\"/1\",\"errors\":[{\"field\":\"birth\",\"code\":\"invalid_format\",\"message\":\"error while parsing 'birth' field from \\\"23/09/92\\\": parsing time \\\"23/09/92\\\" as \\\"02/01/2006\\\": cannot parse \\\"92\\\" as \\\"2006\\\"\"}]}",
+		},
+		{
+			name:         "on invalid If-Match",
+			update:       NewUpdateMock(nil, nil),
+			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
+			ifMatch:      "1",
+			expectedCode: http.StatusPreconditionFailed,
+			expectedBody: "{\"type\":\"urn:users-api:problem:version-mismatch\",\"title\":\"Version mismatch\",\"status\":412,\"detail\":\"app: user has been modified by another request\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on version mismatch",
+			update:       NewUpdateMock(nil, errorspkg.AppVersionMismatch),
+			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
+			ifMatch:      "\"1\"",
+			expectedCode: http.StatusPreconditionFailed,
+			expectedBody: "{\"type\":\"urn:users-api:problem:version-mismatch\",\"title\":\"Version mismatch\",\"status\":412,\"detail\":\"app: user has been modified by another request\",\"instance\":\"/1\"}",
+		},
+		{
+			name:         "on email taken",
+			update:       NewUpdateMock(nil, errorspkg.AppEmailTaken),
+			body:         bytes.NewReader([]byte(`{"name":"test","email":"Test@test.com"}`)),
+			expectedCode: http.StatusConflict,
+			expectedBody: "{\"type\":\"urn:users-api:problem:email-taken\",\"title\":\"Email already taken\",\"status\":409,\"detail\":\"app: email is already taken by another user\",\"instance\":\"/1\"}",
 		},
 		{
 			name:         "on save repository error",
 			update:       NewUpdateMock(nil, errors.New("an error occurred")),
 			body:         bytes.NewReader([]byte(`{"name":"test"}`)),
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: "{\"errors\":\"an error occurred\"}",
+			expectedBody: "{\"type\":\"urn:users-api:problem:internal-error\",\"title\":\"Internal error\",\"status\":500,\"instance\":\"/1\"}",
 		},
 	}
 
@@ -88,14 +150,20 @@ func TestUpdate(t *testing.T) {
 			handler := New(&actions)
 
 			request, _ := http.NewRequest(http.MethodPut, url, test.body)
+			request.Header.Set(ifMatch, test.ifMatch)
 			response := httptest.NewRecorder()
 
 			router := gin.New()
+			router.Use(Errors())
 			router.PUT(url, handler.Update)
 			router.ServeHTTP(response, request)
 
 			assertInt(t, response.Code, test.expectedCode)
+			assertString(t, response.Header().Get("ETag"), test.expectedETag)
 			assertString(t, response.Body.String(), test.expectedBody)
+			if test.expectedFields != nil {
+				assert.Equal(t, test.expectedFields, test.update.fields)
+			}
 		})
 	}
 }
@@ -0,0 +1,88 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"users/domain/entities"
+)
+
+const MaxBatchSize = 1000
+
+type SaveBatch struct {
+	Atomic bool `form:"atomic"`
+}
+
+// ParseSaveBatch decodes a JSON array of users, then decodes and validates every item on its own, so that
+// an invalid item can be reported without rejecting the whole batch. For each position either the user or
+// the error is set.
+func ParseSaveBatch(data []byte) ([]*entities.User, []error, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, nil, errors.New("batch must be a JSON array of users")
+		}
+		return nil, nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, nil, errors.New("batch must contain at least one user")
+	}
+
+	if len(items) > MaxBatchSize {
+		return nil, nil, fmt.Errorf("batch must not contain more than %d users", MaxBatchSize)
+	}
+
+	bodies := make([]SaveUser, len(items))
+	decodeErrs := make([]error, len(items))
+	for i, item := range items {
+		decodeErrs[i] = decodeItem(item, &bodies[i])
+	}
+
+	users, errs := ToUsers(bodies)
+	for i, err := range decodeErrs {
+		if err != nil {
+			users[i], errs[i] = nil, err
+		}
+	}
+
+	return users, errs, nil
+}
+
+// decodeItem decodes an item of a batch, reporting a value of the wrong type against its field.
+func decodeItem(item json.RawMessage, body *SaveUser) error {
+	err := json.Unmarshal(item, body)
+	if err == nil {
+		return nil
+	}
+
+	if fieldErrs := FieldErrors(FromDecodeError(err)); fieldErrs != nil {
+		return fieldErrs
+	}
+
+	return ValidationErrors{{Code: CodeInvalidType, Message: "item must be a JSON object"}}
+}
+
+// ToUsers validates and converts every item. For each position either the user or the error is set.
+func ToUsers(items []SaveUser) ([]*entities.User, []error) {
+	users := make([]*entities.User, len(items))
+	errs := make([]error, len(items))
+
+	for i := range items {
+		users[i], errs[i] = items[i].ToUser()
+	}
+
+	return users, errs
+}
+
+// UpdateBatch applies the same fields to every listed user. The limit on 'ids' matches MaxBatchSize.
+type UpdateBatch struct {
+	IDs    []string    `json:"ids" binding:"required,min=1,max=1000,dive,required"`
+	Fields *UpdateUser `json:"fields" binding:"required"`
+}
+
+// RemoveBatch moves every listed user to the trash. The limit on 'ids' matches MaxBatchSize.
+type RemoveBatch struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=1000,dive,required"`
+}
@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"users/domain/entities"
+)
+
+type cursor struct {
+	Values []string `json:"v"`
+	ID     string   `json:"i"`
+	Sort   string   `json:"s,omitempty"`
+}
+
+func EncodeCursor(c *entities.Cursor) string {
+	if c == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal(cursor{Values: c.Values, ID: c.ID, Sort: entities.FormatSort(c.Sort)})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*entities.Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	if c.ID == "" {
+		return nil, errors.New("missing id")
+	}
+
+	sort, err := parseSort(c.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Cursor{Sort: sort, Values: c.Values, ID: c.ID}, nil
+}
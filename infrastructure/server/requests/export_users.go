@@ -0,0 +1,26 @@
+package requests
+
+import "users/domain/entities"
+
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+type ExportUsers struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+	Sort   string `form:"sort"`
+	FilterUsers
+}
+
+// ToFormat returns the requested format, CSV by default.
+func (p *ExportUsers) ToFormat() string {
+	if p.Format == "" {
+		return ExportCSV
+	}
+	return p.Format
+}
+
+func (p *ExportUsers) ToSort() ([]entities.SortKey, error) {
+	return toSort(p.Sort)
+}
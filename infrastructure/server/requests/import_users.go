@@ -0,0 +1,180 @@
+package requests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"users/domain/entities"
+)
+
+const MaxImportRows = 50000
+
+// byteOrderMark is written by some spreadsheet tools at the start of CSV files.
+const byteOrderMark = "\ufeff"
+
+var importContentTypes = map[string]string{
+	"text/csv":             ExportCSV,
+	"application/x-ndjson": ExportNDJSON,
+	"application/ndjson":   ExportNDJSON,
+}
+
+var importColumns = map[string]bool{
+	"name":     true,
+	"birth":    true,
+	"email":    true,
+	"location": true,
+}
+
+type ImportUsers struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+	DryRun bool   `form:"dry_run"`
+}
+
+// ImportLine is a data line of an import file. Either User or Err is set.
+type ImportLine struct {
+	Line int
+	User *entities.User
+	Err  error
+}
+
+// ToFormat returns the 'format' parameter or, when missing, guesses it from the extension
+// of the uploaded file or from the content type of the request.
+func (p *ImportUsers) ToFormat(contentType string, filename string) (string, error) {
+	if p.Format != "" {
+		return p.Format, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return ExportCSV, nil
+	case ".ndjson", ".jsonl":
+		return ExportNDJSON, nil
+	}
+
+	if format, ok := importContentTypes[contentType]; ok {
+		return format, nil
+	}
+
+	return "", errors.New("unable to tell the format of the file, set the 'format' field to 'csv' or 'ndjson'")
+}
+
+// ParseImport reads every line of the file and converts it with the same rules as SaveUser.
+// Invalid lines are reported in the result; only an unreadable file makes the whole import fail.
+func ParseImport(r io.Reader, format string) ([]ImportLine, error) {
+	if format == ExportNDJSON {
+		return parseNDJSON(r)
+	}
+	return parseCSV(r)
+}
+
+func parseCSV(r io.Reader) ([]ImportLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the file is empty")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, byteOrderMark)))
+		if !importColumns[column] {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		if _, ok := columns[column]; ok {
+			return nil, fmt.Errorf("duplicated column %q", column)
+		}
+		columns[column] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("missing column \"name\"")
+	}
+
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok {
+			return record[i]
+		}
+		return ""
+	}
+
+	var lines []ImportLine
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			lines = append(lines, ImportLine{Line: parseErr.StartLine, Err: parseErr.Err})
+		} else {
+			line, _ := reader.FieldPos(0)
+			lines = append(lines, toImportLine(line, SaveUser{
+				Name:     value(record, "name"),
+				Birth:    value(record, "birth"),
+				Email:    value(record, "email"),
+				Location: value(record, "location"),
+			}))
+		}
+
+		if len(lines) > MaxImportRows {
+			return nil, fmt.Errorf("the file must not contain more than %d users", MaxImportRows)
+		}
+	}
+
+	return lines, nil
+}
+
+func parseNDJSON(r io.Reader) ([]ImportLine, error) {
+	reader := bufio.NewReader(r)
+
+	var lines []ImportLine
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			var item SaveUser
+			if jsonErr := json.Unmarshal(data, &item); jsonErr != nil {
+				lines = append(lines, ImportLine{Line: line, Err: FromDecodeError(jsonErr)})
+			} else {
+				lines = append(lines, toImportLine(line, item))
+			}
+		}
+
+		if len(lines) > MaxImportRows {
+			return nil, fmt.Errorf("the file must not contain more than %d users", MaxImportRows)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+
+	return lines, nil
+}
+
+func toImportLine(line int, item SaveUser) ImportLine {
+	users, errs := ToUsers([]SaveUser{item})
+	return ImportLine{Line: line, User: users[0], Err: errs[0]}
+}
@@ -0,0 +1,147 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"users/domain/entities"
+)
+
+// patchFields tells which fields a JSON Patch can point to and whether they may be removed.
+var patchFields = map[string]bool{
+	"name":     false,
+	"birth":    true,
+	"email":    true,
+	"location": true,
+	"active":   false,
+}
+
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ParseJSONPatch decodes a JSON Patch (RFC 6902) document. Only the 'test', 'replace' and 'remove'
+// operations are supported, on the top-level fields of a user.
+func ParseJSONPatch(data []byte) ([]entities.PatchOperation, error) {
+	var document []JSONPatchOperation
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, FromDecodeError(err)
+	}
+
+	if len(document) == 0 {
+		return nil, errors.New("at least one operation is required")
+	}
+
+	ops := make([]entities.PatchOperation, len(document))
+	for i, operation := range document {
+		op, err := operation.toPatchOperation()
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		ops[i] = op
+	}
+
+	return ops, nil
+}
+
+func (p *JSONPatchOperation) toPatchOperation() (entities.PatchOperation, error) {
+	field, ok := strings.CutPrefix(p.Path, "/")
+	removable, known := patchFields[field]
+	if !ok || !known {
+		return entities.PatchOperation{}, fmt.Errorf("unknown path %q", p.Path)
+	}
+
+	switch p.Op {
+	case entities.PatchTest, entities.PatchReplace:
+		if p.Value == nil {
+			return entities.PatchOperation{}, fmt.Errorf("missing 'value' for %q operation", p.Op)
+		}
+
+		value, err := parsePatchValue(field, p.Value, p.Op == entities.PatchTest)
+		if err != nil {
+			return entities.PatchOperation{}, err
+		}
+
+		return entities.PatchOperation{Op: p.Op, Field: field, Value: value}, nil
+	case entities.PatchRemove:
+		if !removable {
+			return entities.PatchOperation{}, fmt.Errorf("%q cannot be removed", p.Path)
+		}
+
+		return entities.PatchOperation{Op: p.Op, Field: field}, nil
+	}
+
+	return entities.PatchOperation{}, fmt.Errorf("unsupported operation %q", p.Op)
+}
+
+// parsePatchValue converts the value with the same rules as UpdateUser. Only a test may compare
+// a required field with null, which simply never matches, and it is not held to the validation rules.
+func parsePatchValue(field string, data json.RawMessage, test bool) (interface{}, error) {
+	if bytes.Equal(data, []byte("null")) {
+		if !test && !patchFields[field] {
+			return nil, ValidationErrors{*notNull(field)}
+		}
+		return nil, nil
+	}
+
+	if field == "active" {
+		var value bool
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, ValidationErrors{*invalidType(field, "bool")}
+		}
+		return value, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, ValidationErrors{*invalidType(field, "string")}
+	}
+
+	if !test {
+		if err := validatePatchValue(field, value); err != nil {
+			return nil, ValidationErrors{*err}
+		}
+	}
+
+	if field == "name" {
+		return value, nil
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	if field == "birth" {
+		birth, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, ValidationErrors{{
+				Field:   field,
+				Code:    CodeInvalidFormat,
+				Message: fmt.Sprintf("error while parsing 'birth' field from %q: %v", value, err),
+			}}
+		}
+		return birth, nil
+	}
+
+	return value, nil
+}
+
+func validatePatchValue(field string, value string) *FieldError {
+	switch field {
+	case "name":
+		return validateName(value)
+	case "birth":
+		_, err := parseBirthDate(value)
+		return err
+	case "email":
+		return validateEmail(value)
+	case "location":
+		return validateLocation(value)
+	}
+	return nil
+}
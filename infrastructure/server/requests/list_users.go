@@ -0,0 +1,162 @@
+package requests
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"users/domain/entities"
+	"users/domain/errors"
+)
+
+const activeAny = "any"
+
+var sortFields = map[string]entities.SortField{
+	string(entities.SortByName):      entities.SortByName,
+	string(entities.SortByCreatedAt): entities.SortByCreatedAt,
+	string(entities.SortByUpdatedAt): entities.SortByUpdatedAt,
+	string(entities.SortByBirth):     entities.SortByBirth,
+	string(entities.SortByEmail):     entities.SortByEmail,
+}
+
+type ListUsers struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+	FilterUsers
+}
+
+// FilterUsers holds the query parameters shared by every endpoint that lists users.
+type FilterUsers struct {
+	Active      string `form:"active"`
+	Location    string `form:"location"`
+	EmailDomain string `form:"email_domain"`
+	CreatedFrom string `form:"created_from"`
+	CreatedTo   string `form:"created_to"`
+	UpdatedFrom string `form:"updated_from"`
+	UpdatedTo   string `form:"updated_to"`
+	BirthFrom   string `form:"birth_from"`
+	BirthTo     string `form:"birth_to"`
+}
+
+func (p *ListUsers) ToPageRequest() (entities.PageRequest, error) {
+	sort, err := toSort(p.Sort)
+	if err != nil {
+		return entities.PageRequest{}, err
+	}
+
+	after, err := DecodeCursor(p.Cursor)
+	if err != nil {
+		return entities.PageRequest{}, fmt.Errorf("error while parsing 'cursor' field from %q: %w", p.Cursor, err)
+	}
+
+	if after != nil && !after.Matches(sort) {
+		return entities.PageRequest{}, fmt.Errorf("error while parsing 'cursor' field from %q: %w", p.Cursor, errors.AppInvalidCursor)
+	}
+
+	return entities.PageRequest{
+		Limit: p.Limit,
+		Sort:  sort,
+		After: after,
+	}, nil
+}
+
+func (p *FilterUsers) ToFilter() (entities.UserFilter, error) {
+	var filter entities.UserFilter
+
+	switch p.Active {
+	case "":
+		active := true
+		filter.Active = &active
+	case activeAny:
+	default:
+		active, err := strconv.ParseBool(p.Active)
+		if err != nil {
+			return entities.UserFilter{}, fmt.Errorf("error while parsing 'active' field from %q: %w", p.Active, err)
+		}
+		filter.Active = &active
+	}
+
+	filter.Location = toNullableString(p.Location)
+	filter.EmailDomain = toNullableString(p.EmailDomain)
+
+	var err error
+
+	if filter.CreatedAt, err = parseTimeRange("created", time.DateTime, p.CreatedFrom, p.CreatedTo); err != nil {
+		return entities.UserFilter{}, err
+	}
+
+	if filter.UpdatedAt, err = parseTimeRange("updated", time.DateTime, p.UpdatedFrom, p.UpdatedTo); err != nil {
+		return entities.UserFilter{}, err
+	}
+
+	if filter.Birth, err = parseTimeRange("birth", dateLayout, p.BirthFrom, p.BirthTo); err != nil {
+		return entities.UserFilter{}, err
+	}
+
+	return filter, nil
+}
+
+func toSort(value string) ([]entities.SortKey, error) {
+	sort, err := parseSort(value)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing 'sort' field from %q: %w", value, err)
+	}
+
+	if len(sort) == 0 {
+		sort = entities.DefaultSort
+	}
+
+	return sort, nil
+}
+
+func parseSort(value string) ([]entities.SortKey, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var keys []entities.SortKey
+	seen := make(map[entities.SortField]bool)
+
+	for _, term := range strings.Split(value, ",") {
+		var key entities.SortKey
+
+		term = strings.TrimSpace(term)
+		if strings.HasPrefix(term, "-") {
+			key.Desc = true
+			term = term[1:]
+		}
+
+		field, ok := sortFields[term]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q", term)
+		}
+
+		if seen[field] {
+			return nil, fmt.Errorf("duplicated sort field %q", term)
+		}
+		seen[field] = true
+
+		key.Field = field
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func parseTimeRange(name string, layout string, from string, to string) (entities.TimeRange, error) {
+	fromTime, err := parseTime(layout, from)
+	if err != nil {
+		return entities.TimeRange{}, fmt.Errorf("error while parsing '%s_from' field from %q: %w", name, from, err)
+	}
+
+	toTime, err := parseTime(layout, to)
+	if err != nil {
+		return entities.TimeRange{}, fmt.Errorf("error while parsing '%s_to' field from %q: %w", name, to, err)
+	}
+
+	return entities.TimeRange{
+		From: toNullableTime(fromTime),
+		To:   toNullableTime(toTime),
+	}, nil
+}
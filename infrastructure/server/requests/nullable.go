@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable tells apart a JSON field that is missing (Set is false), null (Valid is false) or holds a value.
+type Nullable[T any] struct {
+	Set   bool
+	Valid bool
+	Value T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	var value T
+
+	n.Set = true
+	n.Valid = false
+	n.Value = value
+
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	n.Value = value
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package requests
 
 import (
-	"fmt"
 	"github.com/google/uuid"
 	"time"
 	"users/domain/entities"
@@ -10,16 +9,24 @@ import (
 const dateLayout = "02/01/2006"
 
 type SaveUser struct {
-	Name     string `json:"name" binding:"required"`
+	Name     string `json:"name"`
 	Birth    string `json:"birth"`
 	Email    string `json:"email"`
 	Location string `json:"location"`
 }
 
+// ToUser validates every field and converts the payload. The error lists all the invalid fields.
 func (p *SaveUser) ToUser() (*entities.User, error) {
-	birth, err := parseTime(dateLayout, p.Birth)
-	if err != nil {
-		return nil, fmt.Errorf("error while parsing 'birth' field from %q: %w", p.Birth, err)
+	var errs ValidationErrors
+
+	errs.add(validateName(p.Name))
+	birth, birthErr := parseBirthDate(p.Birth)
+	errs.add(birthErr)
+	errs.add(validateEmail(p.Email))
+	errs.add(validateLocation(p.Location))
+
+	if err := errs.err(); err != nil {
+		return nil, err
 	}
 
 	return &entities.User{
@@ -0,0 +1,6 @@
+package requests
+
+type SearchUsers struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1"`
+}
@@ -1,61 +1,155 @@
 package requests
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
-	"time"
 )
 
+// UpdateUser is the body of both kinds of update. As a merge patch (RFC 7396) a missing field is left
+// unchanged and null clears it; as a replacement every missing or null field is cleared.
+// An empty 'birth', 'email' or 'location' is the same as null.
 type UpdateUser struct {
-	Name     *string `json:"name"`
-	Birth    *string `json:"birth"`
-	Email    *string `json:"email"`
-	Location *string `json:"location"`
-	Active   *string `json:"active"`
+	Name     Nullable[string] `json:"name" swaggertype:"string"`
+	Birth    Nullable[string] `json:"birth" swaggertype:"string"`
+	Email    Nullable[string] `json:"email" swaggertype:"string"`
+	Location Nullable[string] `json:"location" swaggertype:"string"`
+	Active   Nullable[bool]   `json:"active" swaggertype:"boolean"`
 }
 
-func (p *UpdateUser) ToMap() (map[string]interface{}, error) {
-	fields := make(map[string]interface{})
+// UnmarshalJSON decodes each field on its own, so that a value of the wrong type is reported
+// against its field.
+func (p *UpdateUser) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return errors.New("the user must be a JSON object")
+		}
+		return err
+	}
 
-	if p.Name != nil {
-		fields["name"] = *p.Name
+	fields := []struct {
+		name   string
+		target json.Unmarshaler
+	}{
+		{"name", &p.Name},
+		{"birth", &p.Birth},
+		{"email", &p.Email},
+		{"location", &p.Location},
+		{"active", &p.Active},
 	}
 
-	if p.Birth != nil {
-		if *p.Birth == "" {
-			fields["birth"] = nil
-		} else {
-			value, err := time.Parse(dateLayout, *p.Birth)
-			if err != nil {
-				return nil, fmt.Errorf("error while parsing 'birth' field from %q: %w", *p.Birth, err)
+	var errs ValidationErrors
+	for _, field := range fields {
+		value, ok := raw[field.name]
+		if !ok {
+			continue
+		}
+
+		if err := field.target.UnmarshalJSON(value); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return err
 			}
-			fields["birth"] = value
+			errs.add(invalidType(field.name, typeErr.Type.String()))
 		}
 	}
 
-	if p.Email != nil {
-		if *p.Email == "" {
-			fields["email"] = nil
+	return errs.err()
+}
+
+// ToMap returns the fields changed by the merge patch. The error lists all the invalid fields.
+func (p *UpdateUser) ToMap() (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	var errs ValidationErrors
+
+	if p.Name.Set {
+		if !p.Name.Valid {
+			errs.add(notNull("name"))
 		} else {
-			fields["email"] = *p.Email
+			errs.add(validateName(p.Name.Value))
+			fields["name"] = p.Name.Value
 		}
 	}
 
-	if p.Location != nil {
-		if *p.Location == "" {
-			fields["location"] = nil
-		} else {
-			fields["location"] = *p.Location
-		}
+	if p.Birth.Set {
+		birth, birthErr := parseBirth(p.Birth)
+		errs.add(birthErr)
+		fields["birth"] = birth
+	}
+
+	if p.Email.Set {
+		errs.add(validateEmail(p.Email.Value))
+		fields["email"] = toNullableField(p.Email)
+	}
+
+	if p.Location.Set {
+		errs.add(validateLocation(p.Location.Value))
+		fields["location"] = toNullableField(p.Location)
 	}
 
-	if p.Active != nil {
-		active, err := strconv.ParseBool(*p.Active)
-		if err != nil {
-			return nil, fmt.Errorf("error while parsing 'active' field from %q: %w", *p.Active, err)
+	if p.Active.Set {
+		if !p.Active.Valid {
+			errs.add(notNull("active"))
 		}
-		fields["active"] = active
+		fields["active"] = p.Active.Value
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
 	}
 
 	return fields, nil
 }
+
+// ToReplaceMap returns every field of the user, as a full replacement: 'name' is required,
+// the other fields are cleared when missing and 'active' defaults to true, as on creation.
+// The error lists all the invalid fields.
+func (p *UpdateUser) ToReplaceMap() (map[string]interface{}, error) {
+	var errs ValidationErrors
+
+	errs.add(validateName(p.Name.Value))
+	birth, birthErr := parseBirth(p.Birth)
+	errs.add(birthErr)
+	errs.add(validateEmail(p.Email.Value))
+	errs.add(validateLocation(p.Location.Value))
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	active := true
+	if p.Active.Valid {
+		active = p.Active.Value
+	}
+
+	return map[string]interface{}{
+		"name":     p.Name.Value,
+		"birth":    birth,
+		"email":    toNullableField(p.Email),
+		"location": toNullableField(p.Location),
+		"active":   active,
+	}, nil
+}
+
+// parseBirth returns the birth date, or nil if the field is missing, null or empty.
+func parseBirth(value Nullable[string]) (interface{}, *FieldError) {
+	birth, err := parseBirthDate(value.Value)
+	if err != nil || birth.IsZero() {
+		return nil, err
+	}
+	return birth, nil
+}
+
+func notNull(field string) *FieldError {
+	return &FieldError{Field: field, Code: CodeNotNull, Message: fmt.Sprintf("'%s' field cannot be null", field)}
+}
+
+// toNullableField returns the value of a text field, or nil if it is missing, null or empty.
+func toNullableField(value Nullable[string]) interface{} {
+	if !value.Valid || value.Value == "" {
+		return nil
+	}
+	return value.Value
+}
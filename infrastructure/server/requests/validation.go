@@ -0,0 +1,245 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"net/mail"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxNameLength     = 100
+	MaxEmailLength    = 254
+	MaxLocationLength = 255
+)
+
+// Codes of the field errors, stable for clients to rely on.
+const (
+	CodeRequired      = "required"
+	CodeNotNull       = "not_null"
+	CodeInvalidType   = "invalid_type"
+	CodeInvalidFormat = "invalid_format"
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeInFuture      = "in_future"
+)
+
+// FieldError is a rule broken by a field of a request payload.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors lists every invalid field of a payload.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add appends the error if it is not nil.
+func (e *ValidationErrors) add(fieldErr *FieldError) {
+	if fieldErr != nil {
+		*e = append(*e, *fieldErr)
+	}
+}
+
+// err returns the errors, or nil if there are none.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// FieldErrors returns the field errors held by err, or nil if it holds none.
+func FieldErrors(err error) ValidationErrors {
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return validationErrs
+	}
+	return nil
+}
+
+// FromDecodeError reports a value of the wrong type against its field, or returns err as is.
+func FromDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return ValidationErrors{*invalidType(typeErr.Field, typeErr.Type.String())}
+	}
+	return err
+}
+
+// FromBindError reports the values of the wrong type and the binding rules broken by a payload bound to v
+// against their field, named as in the JSON payload, or returns err as is.
+func FromBindError(err error, v any) error {
+	var ruleErrs validator.ValidationErrors
+	if !errors.As(err, &ruleErrs) {
+		return FromDecodeError(err)
+	}
+
+	var errs ValidationErrors
+	for _, ruleErr := range ruleErrs {
+		errs.add(brokenRule(jsonPath(reflect.TypeOf(v), ruleErr.StructNamespace()), ruleErr))
+	}
+	return errs.err()
+}
+
+func brokenRule(field string, ruleErr validator.FieldError) *FieldError {
+	unit := "character"
+	if kind := ruleErr.Kind(); kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+		unit = "item"
+	}
+	if ruleErr.Param() != "1" {
+		unit += "s"
+	}
+
+	switch ruleErr.Tag() {
+	case "required":
+		return &FieldError{Field: field, Code: CodeRequired, Message: fmt.Sprintf("'%s' field is required", field)}
+	case "min":
+		return &FieldError{
+			Field:   field,
+			Code:    CodeTooShort,
+			Message: fmt.Sprintf("'%s' field must contain at least %s %s", field, ruleErr.Param(), unit),
+		}
+	case "max":
+		return &FieldError{
+			Field:   field,
+			Code:    CodeTooLong,
+			Message: fmt.Sprintf("'%s' field must not contain more than %s %s", field, ruleErr.Param(), unit),
+		}
+	}
+
+	return &FieldError{
+		Field:   field,
+		Code:    CodeInvalidFormat,
+		Message: fmt.Sprintf("'%s' field breaks the '%s' rule", field, ruleErr.Tag()),
+	}
+}
+
+// jsonPath turns the struct namespace of a validation error, such as "RemoveBatch.IDs[2]", into the path
+// of the value in the JSON payload, such as "ids.2".
+func jsonPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index, indexed := strings.Cut(segment, "[")
+
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return strings.Join(append(path, segment), ".")
+		}
+
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+			name = tag
+		}
+		path = append(path, name)
+
+		t = field.Type
+		if indexed {
+			path = append(path, strings.TrimSuffix(index, "]"))
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+				t = t.Elem()
+			}
+		}
+	}
+
+	return strings.Join(path, ".")
+}
+
+func invalidType(field string, kind string) *FieldError {
+	return &FieldError{
+		Field:   field,
+		Code:    CodeInvalidType,
+		Message: fmt.Sprintf("'%s' field must be of type %s", field, kind),
+	}
+}
+
+func validateName(value string) *FieldError {
+	if value == "" {
+		return &FieldError{Field: "name", Code: CodeRequired, Message: "'name' field is required"}
+	}
+	return validateLength("name", value, MaxNameLength)
+}
+
+func validateEmail(value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+
+	if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+		return &FieldError{
+			Field:   "email",
+			Code:    CodeInvalidFormat,
+			Message: fmt.Sprintf("'email' field must be a valid email address, got %q", value),
+		}
+	}
+
+	return validateLength("email", value, MaxEmailLength)
+}
+
+func validateLocation(value string) *FieldError {
+	return validateLength("location", value, MaxLocationLength)
+}
+
+// parseBirthDate returns the birth date, or the zero time if value is empty. A birth date cannot be
+// later than today.
+func parseBirthDate(value string) (time.Time, *FieldError) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	birth, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, &FieldError{
+			Field:   "birth",
+			Code:    CodeInvalidFormat,
+			Message: fmt.Sprintf("error while parsing 'birth' field from %q: %v", value, err),
+		}
+	}
+
+	if birth.After(time.Now()) {
+		return time.Time{}, &FieldError{
+			Field:   "birth",
+			Code:    CodeInFuture,
+			Message: fmt.Sprintf("'birth' field cannot be in the future, got %q", value),
+		}
+	}
+
+	return birth, nil
+}
+
+func validateLength(field string, value string, limit int) *FieldError {
+	if utf8.RuneCountInString(value) <= limit {
+		return nil
+	}
+
+	return &FieldError{
+		Field:   field,
+		Code:    CodeTooLong,
+		Message: fmt.Sprintf("'%s' field must not be longer than %d characters", field, limit),
+	}
+}
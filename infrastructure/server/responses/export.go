@@ -0,0 +1,117 @@
+package responses
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"users/domain/entities"
+)
+
+var csvHeader = []string{"id", "name", "birth", "email", "location", "created_at", "updated_at", "active"}
+
+// formulaPrefixes are the first characters that make a spreadsheet read a cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// UserEncoder writes users one by one in an export format.
+// Close must be called once every user has been written; Fail ends an export that could not be completed
+// with a line reporting the message, so that a truncated file cannot be taken for a complete one.
+type UserEncoder interface {
+	Encode(user *entities.User) error
+	Close() error
+	Fail(message string) error
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+	header bool
+}
+
+// NewCSVEncoder writes a header line followed by one line per user, with the values formatted as in UserResponse.
+// Values that a spreadsheet would evaluate as a formula are prefixed with a single quote.
+func NewCSVEncoder(w io.Writer) UserEncoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(user *entities.User) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	response := FromUser(user)
+
+	return e.writer.Write([]string{
+		response.ID,
+		escapeFormula(response.Name),
+		response.Birth,
+		escapeFormula(response.Email),
+		escapeFormula(fromNullableString(response.Location)),
+		response.CreatedAt,
+		response.UpdatedAt,
+		strconv.FormatBool(response.Active),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// Fail writes a last line made of the "#error" marker and the message, which has not the columns of a user.
+func (e *csvEncoder) Fail(message string) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	if err := e.writer.Write([]string{"#error", message}); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.writer.Write(csvHeader)
+}
+
+// escapeFormula prefixes a value with a single quote when it starts with a formula character, after any
+// quote it already has, so that a spreadsheet shows the value as it is stored.
+func escapeFormula(value string) string {
+	unquoted := strings.TrimLeft(value, "'")
+	if unquoted != "" && strings.ContainsRune(formulaPrefixes, rune(unquoted[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONEncoder writes every user as a UserResponse object on its own line.
+func NewNDJSONEncoder(w io.Writer) UserEncoder {
+	return &ndjsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) Encode(user *entities.User) error {
+	return e.encoder.Encode(FromUser(user))
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+// Fail writes a last object holding only an "error" member with the message.
+func (e *ndjsonEncoder) Fail(message string) error {
+	return e.encoder.Encode(map[string]string{"error": message})
+}
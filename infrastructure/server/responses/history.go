@@ -0,0 +1,52 @@
+package responses
+
+import (
+	"time"
+	"users/domain/entities"
+)
+
+type UserHistoryResponse struct {
+	ID            int64         `json:"id"`
+	UserID        string        `json:"user_id"`
+	Action        string        `json:"action"`
+	Before        *UserResponse `json:"before"`
+	After         *UserResponse `json:"after"`
+	ChangedFields []string      `json:"changed_fields"`
+	Actor         *string       `json:"actor"`
+	TraceID       *string       `json:"trace_id"`
+	CreatedAt     string        `json:"created_at"`
+}
+
+func FromUserHistory(history *entities.UserHistory) *UserHistoryResponse {
+	changedFields := history.ChangedFields
+	if changedFields == nil {
+		changedFields = []string{}
+	}
+
+	return &UserHistoryResponse{
+		ID:            history.ID,
+		UserID:        history.UserID,
+		Action:        history.Action,
+		Before:        fromNullableUser(history.Before),
+		After:         fromNullableUser(history.After),
+		ChangedFields: changedFields,
+		Actor:         history.Actor,
+		TraceID:       history.TraceID,
+		CreatedAt:     history.CreatedAt.Format(time.DateTime),
+	}
+}
+
+func FromUserHistoryList(history []*entities.UserHistory) []*UserHistoryResponse {
+	result := make([]*UserHistoryResponse, len(history))
+	for i, entry := range history {
+		result[i] = FromUserHistory(entry)
+	}
+	return result
+}
+
+func fromNullableUser(user *entities.User) *UserResponse {
+	if user == nil {
+		return nil
+	}
+	return FromUser(user)
+}
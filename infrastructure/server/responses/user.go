@@ -1,12 +1,16 @@
 package responses
 
 import (
+	"strconv"
 	"time"
 	"users/domain/entities"
+	"users/infrastructure/server/requests"
 )
 
 const dateLayout = "02/01/2006"
 
+// UserResponse is a user as sent to clients. ETag is the value to send back in If-Match to modify it,
+// the same as the ETag header of single-user responses.
 type UserResponse struct {
 	ID        string  `json:"id"`
 	Name      string  `json:"name"`
@@ -16,6 +20,8 @@ type UserResponse struct {
 	CreatedAt string  `json:"created_at"`
 	UpdatedAt string  `json:"updated_at"`
 	Active    bool    `json:"active"`
+	ETag      string  `json:"etag"`
+	DeletedAt *string `json:"deleted_at,omitempty"`
 }
 
 func FromUser(user *entities.User) *UserResponse {
@@ -28,9 +34,15 @@ func FromUser(user *entities.User) *UserResponse {
 		CreatedAt: user.CreatedAt.Format(time.DateTime),
 		UpdatedAt: user.UpdatedAt.Format(time.DateTime),
 		Active:    user.Active,
+		ETag:      ETag(user),
+		DeletedAt: fromNullableDateTime(user.DeletedAt),
 	}
 }
 
+func ETag(user *entities.User) string {
+	return strconv.Quote(strconv.FormatInt(user.Version, 10))
+}
+
 func FromUserList(users []*entities.User) []*UserResponse {
 	result := make([]*UserResponse, len(users))
 	for i, user := range users {
@@ -39,6 +51,18 @@ func FromUserList(users []*entities.User) []*UserResponse {
 	return result
 }
 
+type UserPage struct {
+	Data       []*UserResponse `json:"data"`
+	NextCursor *string         `json:"next_cursor"`
+}
+
+func FromUserPage(users []*entities.User, nextCursor string) *UserPage {
+	return &UserPage{
+		Data:       FromUserList(users),
+		NextCursor: toNullableString(nextCursor),
+	}
+}
+
 func fromNullableString(s *string) string {
 	if s != nil {
 		return *s
@@ -46,9 +70,96 @@ func fromNullableString(s *string) string {
 	return ""
 }
 
+func toNullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func fromNullableDateTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	value := t.Format(time.DateTime)
+	return &value
+}
+
 func fromNullableTime(t *time.Time) string {
 	if t != nil {
 		return (*t).Format(dateLayout)
 	}
 	return ""
 }
+
+// BatchItemResponse is the outcome of an item of a batch: the created user, or the error reported with the
+// title or detail and the invalid fields a problem would carry.
+type BatchItemResponse struct {
+	Index  int                   `json:"index"`
+	Status int                   `json:"status"`
+	Data   *UserResponse         `json:"data,omitempty"`
+	Detail string                `json:"detail,omitempty"`
+	Errors []requests.FieldError `json:"errors,omitempty"`
+}
+
+// FromBatchItem describes an item that was created.
+func FromBatchItem(index int, status int, user *entities.User) *BatchItemResponse {
+	return &BatchItemResponse{
+		Index:  index,
+		Status: status,
+		Data:   FromUser(user),
+	}
+}
+
+// FromBatchItemError describes an item that failed, listing its invalid fields when they are known.
+func FromBatchItemError(index int, status int, detail string, fieldErrs []requests.FieldError) *BatchItemResponse {
+	return &BatchItemResponse{
+		Index:  index,
+		Status: status,
+		Detail: detail,
+		Errors: fieldErrs,
+	}
+}
+
+type BatchUpdateResponse struct {
+	Updated  []*UserResponse `json:"updated"`
+	NotFound []string        `json:"not_found"`
+}
+
+func FromBatchUpdate(change *entities.BatchChange) *BatchUpdateResponse {
+	return &BatchUpdateResponse{
+		Updated:  FromUserList(change.Users),
+		NotFound: change.NotFound,
+	}
+}
+
+type BatchRemoveResponse struct {
+	Removed  []string `json:"removed"`
+	NotFound []string `json:"not_found"`
+}
+
+func FromBatchRemove(change *entities.BatchChange) *BatchRemoveResponse {
+	removed := make([]string, len(change.Users))
+	for i, user := range change.Users {
+		removed[i] = user.ID
+	}
+
+	return &BatchRemoveResponse{
+		Removed:  removed,
+		NotFound: change.NotFound,
+	}
+}
+
+type ImportReport struct {
+	DryRun  bool                 `json:"dry_run"`
+	Total   int                  `json:"total"`
+	Valid   int                  `json:"valid"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Errors  []*ImportLineFailure `json:"errors"`
+}
+
+type ImportLineFailure struct {
+	Line   int    `json:"line"`
+	Errors string `json:"errors"`
+}
@@ -6,18 +6,31 @@ import (
 	"users/infrastructure/server/handlers"
 )
 
-func Setup(baseRouter *gin.RouterGroup, actions *dependencies.Actions) *gin.RouterGroup {
+func Setup(baseRouter *gin.RouterGroup, actions *dependencies.Actions, adminKey string) *gin.RouterGroup {
 	handler := handlers.New(actions)
 
-	prefix := baseRouter.Group("/users")
+	prefix := baseRouter.Group("/users", handlers.Actor())
 
 	prefix.GET("", handler.Get)
 	prefix.POST("", handler.Save)
+	prefix.POST("/batch", handler.SaveBatch)
+	prefix.PATCH("/batch", handler.UpdateBatch)
+	prefix.DELETE("/batch", handler.RemoveBatch)
 	prefix.PUT(":id", handler.Update)
+	prefix.PATCH(":id", handler.Patch)
 	prefix.DELETE(":id", handler.Remove)
+	prefix.POST(":id/restore", handler.Restore)
+	prefix.GET(":id/history", handler.GetHistory)
+	prefix.GET("/deleted", handler.GetDeleted)
+	prefix.GET("/export", handler.Export)
+	prefix.POST("/import", handler.Import)
 
+	prefix.GET("/search", handler.Search)
 	prefix.POST("/search", handler.GetMultiple)
 	prefix.GET("/search/:id", handler.GetSingle)
 
+	admin := baseRouter.Group("/admin/users", handlers.Admin(adminKey), handlers.Actor())
+	admin.DELETE(":id", handler.Purge)
+
 	return prefix
 }
@@ -14,13 +14,13 @@ import (
 
 func Setup(config *Config, actions *dependencies.Actions) *http.Server {
 	ginServer := gin.New()
-	ginServer.Use(otelgin.Middleware("app-server-gin"))
+	ginServer.Use(otelgin.Middleware("app-server-gin"), handlers.Errors())
 
 	router := ginServer.Group(config.Prefix)
 	router.GET("/health", handlers.HealthCheck)
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	routes.Setup(router, actions)
+	routes.Setup(router, actions, config.AdminKey)
 
 	return &http.Server{
 		Addr:         fmt.Sprintf(":%d", config.Port),
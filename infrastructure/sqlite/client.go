@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "modernc.org/sqlite"
+	"net/url"
+)
+
+// Transactions take the write lock when they begin, so the rows a transaction reads cannot change
+// before it ends. Other connections wait for the lock instead of failing at once.
+const connectionOptions = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate"
+
+type Client struct {
+	db      *sql.DB
+	queries *Queries
+}
+
+func NewClient(config *Config) (*Client, error) {
+	db, err := sql.Open("sqlite", config.uri())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open db: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to ping db: %w", err), db.Close())
+	}
+
+	return &Client{
+		db:      db,
+		queries: New(db),
+	}, nil
+}
+
+// uri is the URI SQLite opens the database with. The path is escaped, since SQLite decodes it and would
+// read '?' or '#' as the end of the path.
+func (c *Config) uri() string {
+	escaped := (&url.URL{Path: c.Path}).EscapedPath()
+	return (&url.URL{Scheme: "file", Opaque: escaped, RawQuery: connectionOptions}).String()
+}
+
+func (c *Client) Close() {
+	_ = c.db.Close()
+}
+
+func (c *Client) Migrate() error {
+	driver, err := sqlite.WithInstance(c.db, &sqlite.Config{})
+	if err != nil {
+		return err
+	}
+
+	migration, err := migrate.NewWithDatabaseInstance("file://migrations/sqlite/", "sqlite", driver)
+	if err != nil {
+		return err
+	}
+
+	if err = migration.Up(); err != nil {
+		if !errors.Is(err, migrate.ErrNoChange) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inTx runs fn inside a transaction, which is committed only if fn succeeds.
+func (c *Client) inTx(ctx context.Context, fn func(*Queries) error) (err error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, ignoreDone(tx.Rollback()))
+		}
+	}()
+
+	if err = fn(c.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ignoreDone drops the error of rolling back a transaction that has already ended.
+func ignoreDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientEscapesPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users?mode=ro#100%.db")
+
+	config, err := NewConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err = client.db.Exec("CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(path); err != nil {
+		t.Errorf("got '%v', want the database at %q", err, path)
+	}
+}
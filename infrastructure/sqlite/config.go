@@ -0,0 +1,19 @@
+package sqlite
+
+import (
+	"users/domain/errors"
+)
+
+type Config struct {
+	Path string
+}
+
+func NewConfig(path string) (*Config, error) {
+	if path == "" {
+		return nil, errors.SQLiteMissingPath
+	}
+
+	return &Config{
+		Path: path,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+	"users/domain"
+	"users/domain/entities"
+)
+
+type snapshot struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Birth     *time.Time `json:"birth"`
+	Email     *string    `json:"email"`
+	Location  *string    `json:"location"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Active    bool       `json:"active"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+func recordHistory(ctx context.Context, queries *Queries, action string, before *entities.User, after *entities.User) error {
+	arg, err := toCreateUserHistoryParams(ctx, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	return queries.CreateUserHistory(ctx, arg)
+}
+
+func toCreateUserHistoryParams(ctx context.Context, action string, before *entities.User, after *entities.User) (CreateUserHistoryParams, error) {
+	var arg CreateUserHistoryParams
+
+	if before != nil {
+		arg.UserID = before.ID
+	} else {
+		arg.UserID = after.ID
+	}
+
+	var err error
+
+	if arg.Before, err = toSnapshot(before); err != nil {
+		return CreateUserHistoryParams{}, err
+	}
+
+	if arg.After, err = toSnapshot(after); err != nil {
+		return CreateUserHistoryParams{}, err
+	}
+
+	changedFields, err := json.Marshal(entities.ChangedFields(before, after))
+	if err != nil {
+		return CreateUserHistoryParams{}, err
+	}
+
+	arg.Action = action
+	arg.ChangedFields = string(changedFields)
+	arg.CreatedAt = formatTime(time.Now())
+
+	if actor := domain.ActorFromContext(ctx); actor != "" {
+		arg.Actor = sql.NullString{String: actor, Valid: true}
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		arg.TraceID = sql.NullString{String: spanContext.TraceID().String(), Valid: true}
+	}
+
+	return arg, nil
+}
+
+func toSnapshot(user *entities.User) (sql.NullString, error) {
+	if user == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(snapshot{
+		ID:        user.ID,
+		Name:      user.Name,
+		Birth:     user.Birth,
+		Email:     user.Email,
+		Location:  user.Location,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Active:    user.Active,
+		Version:   user.Version,
+		DeletedAt: user.DeletedAt,
+	})
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func fromSnapshot(data sql.NullString) (*entities.User, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+
+	var value snapshot
+	if err := json.Unmarshal([]byte(data.String), &value); err != nil {
+		return nil, err
+	}
+
+	return &entities.User{
+		ID:        value.ID,
+		Name:      value.Name,
+		Birth:     value.Birth,
+		Email:     value.Email,
+		Location:  value.Location,
+		CreatedAt: value.CreatedAt,
+		UpdatedAt: value.UpdatedAt,
+		Active:    value.Active,
+		Version:   value.Version,
+		DeletedAt: value.DeletedAt,
+	}, nil
+}
+
+func toUserHistory(row UserHistory) (*entities.UserHistory, error) {
+	before, err := fromSnapshot(row.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := fromSnapshot(row.After)
+	if err != nil {
+		return nil, err
+	}
+
+	var changedFields []string
+	if err = json.Unmarshal([]byte(row.ChangedFields), &changedFields); err != nil {
+		return nil, err
+	}
+
+	createdAt, err := parseTime(row.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var actor *string
+	if row.Actor.Valid {
+		actor = &row.Actor.String
+	}
+
+	var traceID *string
+	if row.TraceID.Valid {
+		traceID = &row.TraceID.String
+	}
+
+	return &entities.UserHistory{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		Action:        row.Action,
+		Before:        before,
+		After:         after,
+		ChangedFields: changedFields,
+		Actor:         actor,
+		TraceID:       traceID,
+		CreatedAt:     createdAt,
+	}, nil
+}
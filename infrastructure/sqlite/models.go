@@ -0,0 +1,34 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package sqlite
+
+import (
+	"database/sql"
+)
+
+type User struct {
+	ID        string
+	Name      string
+	Birth     sql.NullString
+	Email     sql.NullString
+	Location  sql.NullString
+	CreatedAt string
+	UpdatedAt string
+	Active    bool
+	Version   int64
+	DeletedAt sql.NullString
+}
+
+type UserHistory struct {
+	ID            int64
+	UserID        string
+	Action        string
+	Before        sql.NullString
+	After         sql.NullString
+	ChangedFields string
+	Actor         sql.NullString
+	TraceID       sql.NullString
+	CreatedAt     string
+}
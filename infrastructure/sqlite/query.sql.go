@@ -0,0 +1,490 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: query.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (
+  id, name, birth, email, location, created_at, updated_at, active
+) VALUES (
+  ?1, ?2, ?3, ?4, ?5, ?6, ?6, ?7
+)
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+type CreateUserParams struct {
+	ID        string
+	Name      string
+	Birth     sql.NullString
+	Email     sql.NullString
+	Location  sql.NullString
+	CreatedAt string
+	Active    bool
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.ID,
+		arg.Name,
+		arg.Birth,
+		arg.Email,
+		arg.Location,
+		arg.CreatedAt,
+		arg.Active,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createUserHistory = `-- name: CreateUserHistory :exec
+INSERT INTO user_history (
+  user_id, action, before, after, changed_fields, actor, trace_id, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
+`
+
+type CreateUserHistoryParams struct {
+	UserID        string
+	Action        string
+	Before        sql.NullString
+	After         sql.NullString
+	ChangedFields string
+	Actor         sql.NullString
+	TraceID       sql.NullString
+	CreatedAt     string
+}
+
+func (q *Queries) CreateUserHistory(ctx context.Context, arg CreateUserHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createUserHistory,
+		arg.UserID,
+		arg.Action,
+		arg.Before,
+		arg.After,
+		arg.ChangedFields,
+		arg.Actor,
+		arg.TraceID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const deleteUser = `-- name: DeleteUser :one
+UPDATE users
+SET updated_at = ?1, deleted_at = ?1, version = version + 1
+WHERE id = ?2
+  AND deleted_at IS NULL
+  AND (CAST(?3 AS INTEGER) = 0 OR version = ?3)
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+type DeleteUserParams struct {
+	DeletedAt       string
+	ID              string
+	ExpectedVersion int64
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, deleteUser, arg.DeletedAt, arg.ID, arg.ExpectedVersion)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findUser = `-- name: FindUser :one
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) FindUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findUsers = `-- name: FindUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) FindUsers(ctx context.Context, ids []string) ([]User, error) {
+	query := findUsers
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUsers(ctx context.Context, ids []string) ([]User, error) {
+	query := getUsers
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hardDeleteUser = `-- name: HardDeleteUser :execrows
+DELETE FROM users
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) HardDeleteUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, hardDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listDeletedUsers = `-- name: ListDeletedUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+`
+
+func (q *Queries) ListDeletedUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserHistory = `-- name: ListUserHistory :many
+SELECT id, user_id, "action", "before", "after", changed_fields, actor, trace_id, created_at FROM user_history
+WHERE user_id = ?
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListUserHistory(ctx context.Context, userID string) ([]UserHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listUserHistory, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserHistory
+	for rows.Next() {
+		var i UserHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Action,
+			&i.Before,
+			&i.After,
+			&i.ChangedFields,
+			&i.Actor,
+			&i.TraceID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, updated_at = ?1, version = version + 1
+WHERE id = ?2 AND deleted_at IS NOT NULL
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+type RestoreUserParams struct {
+	UpdatedAt string
+	ID        string
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, arg.UpdatedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, name, birth, email, location, created_at, updated_at, active, version, deleted_at FROM users
+WHERE deleted_at IS NULL
+  AND (instr(lower(name), lower(?1)) > 0
+   OR instr(lower(COALESCE(email, '')), lower(?1)) > 0
+   OR instr(lower(COALESCE(location, '')), lower(?1)) > 0)
+ORDER BY name, id
+LIMIT ?2
+`
+
+type SearchUsersParams struct {
+	Query      string
+	MaxResults int64
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Birth,
+			&i.Email,
+			&i.Location,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Active,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET
+  name = CASE WHEN CAST(?1 AS BOOLEAN)
+  THEN ?2 ELSE name END,
+
+  birth = CASE WHEN CAST(?3 AS BOOLEAN)
+  THEN ?4 ELSE birth END,
+
+  email = CASE WHEN CAST(?5 AS BOOLEAN)
+  THEN ?6 ELSE email END,
+
+  location = CASE WHEN CAST(?7 AS BOOLEAN)
+  THEN ?8 ELSE location END,
+
+  active = CASE WHEN CAST(?9 AS BOOLEAN)
+  THEN ?10 ELSE active END,
+
+  updated_at = ?11,
+  version = version + 1
+WHERE id = ?12
+  AND deleted_at IS NULL
+  AND (CAST(?13 AS INTEGER) = 0 OR version = ?13)
+RETURNING id, name, birth, email, location, created_at, updated_at, active, version, deleted_at
+`
+
+type UpdateUserParams struct {
+	NameDoUpdate     bool
+	Name             string
+	BirthDoUpdate    bool
+	Birth            sql.NullString
+	EmailDoUpdate    bool
+	Email            sql.NullString
+	LocationDoUpdate bool
+	Location         sql.NullString
+	ActiveDoUpdate   bool
+	Active           bool
+	UpdatedAt        string
+	ID               string
+	ExpectedVersion  int64
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.NameDoUpdate,
+		arg.Name,
+		arg.BirthDoUpdate,
+		arg.Birth,
+		arg.EmailDoUpdate,
+		arg.Email,
+		arg.LocationDoUpdate,
+		arg.Location,
+		arg.ActiveDoUpdate,
+		arg.Active,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Birth,
+		&i.Email,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,714 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"strings"
+	"time"
+	"users/domain/entities"
+	domainErrors "users/domain/errors"
+)
+
+const (
+	emailIndex = "users_email_lower_idx"
+
+	// Layouts of the stored timestamps and dates, the same ones the cursors hold.
+	timeLayout = "2006-01-02T15:04:05.000000"
+	dateLayout = time.DateOnly
+)
+
+type Repository struct {
+	client *Client
+	tracer trace.Tracer
+}
+
+func NewRepository(
+	client *Client,
+) (*Repository, error) {
+	return &Repository{
+		client: client,
+		tracer: otel.Tracer("SQLiteRepository")}, nil
+}
+
+func (repo *Repository) Get(ctx context.Context, filter entities.UserFilter, page entities.PageRequest) (*entities.Page, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Get")
+	defer span.End()
+
+	query, args, err := buildListUsers(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*entities.User
+	err = repo.query(tracerCtx, query, args, func(user *entities.User) error {
+		users = append(users, user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("repo.sqlite.rows.count", len(users)))
+
+	var next *entities.Cursor
+	if len(users) > page.Limit {
+		users = users[:page.Limit]
+		next = entities.NewCursor(users[len(users)-1], page.Sort)
+	}
+
+	if users == nil {
+		users = []*entities.User{}
+	}
+
+	return &entities.Page{Users: users, Next: next}, nil
+}
+
+// Export hands the users to fn one at a time while they are read from the database,
+// so the result set is never held in memory.
+func (repo *Repository) Export(ctx context.Context, filter entities.UserFilter, sort []entities.SortKey, fn func(*entities.User) error) error {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Export")
+	defer span.End()
+
+	query, args, err := buildExportUsers(filter, sort)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = repo.query(tracerCtx, query, args, func(user *entities.User) error {
+		count++
+		return fn(user)
+	})
+
+	span.SetAttributes(attribute.Int("repo.sqlite.rows.count", count))
+
+	return err
+}
+
+// query runs a query built for the users table and hands each user to fn.
+func (repo *Repository) query(ctx context.Context, query string, args []interface{}, fn func(*entities.User) error) error {
+	rows, err := repo.client.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row User
+		err = rows.Scan(
+			&row.ID,
+			&row.Name,
+			&row.Birth,
+			&row.Email,
+			&row.Location,
+			&row.CreatedAt,
+			&row.UpdatedAt,
+			&row.Active,
+			&row.Version,
+			&row.DeletedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		user, err := toUser(row)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(user); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (repo *Repository) GetByID(ctx context.Context, ids []string) ([]*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-GetByID")
+	defer span.End()
+
+	users, err := txGetByID(tracerCtx, repo.client.queries, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("repo.sqlite.rows.count", len(users)))
+
+	return users, nil
+}
+
+// Search matches the query as a substring of the name, email or location, ignoring case.
+// SQLite has no trigram similarity, so the matches are ordered by name.
+func (repo *Repository) Search(ctx context.Context, query string, limit int) ([]*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Search")
+	defer span.End()
+
+	rows, err := repo.client.queries.SearchUsers(tracerCtx, SearchUsersParams{
+		Query:      query,
+		MaxResults: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("repo.sqlite.rows.count", len(rows)))
+
+	return toUserList(rows)
+}
+
+func (repo *Repository) Save(ctx context.Context, user *entities.User) (*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Save")
+	defer span.End()
+
+	var result *entities.User
+	err := repo.client.inTx(tracerCtx, func(queries *Queries) (err error) {
+		result, err = txSave(tracerCtx, queries, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SaveBatch inserts the users one by one in a single transaction. A failing insert only undoes
+// itself, so the rest of the batch goes on unless the batch is atomic.
+func (repo *Repository) SaveBatch(ctx context.Context, users []*entities.User, atomic bool) ([]*entities.BatchResult, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-SaveBatch")
+	defer span.End()
+
+	results := make([]*entities.BatchResult, len(users))
+
+	err := repo.client.inTx(tracerCtx, func(queries *Queries) error {
+		for i, user := range users {
+			row, err := queries.CreateUser(tracerCtx, toSaveUserParams(user))
+			if err != nil {
+				results[i] = &entities.BatchResult{Err: toDomainError(err)}
+				if atomic {
+					return domainErrors.AppBatchAborted
+				}
+				continue
+			}
+
+			created, err := toUser(row)
+			if err != nil {
+				return err
+			}
+
+			if err = recordHistory(tracerCtx, queries, entities.HistoryCreated, nil, created); err != nil {
+				return err
+			}
+
+			results[i] = &entities.BatchResult{User: created}
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, domainErrors.AppBatchAborted) {
+		for i, result := range results {
+			if result == nil || result.Err == nil {
+				results[i] = &entities.BatchResult{Err: domainErrors.AppBatchAborted}
+			}
+		}
+		return results, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo *Repository) Update(ctx context.Context, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Update")
+	defer span.End()
+
+	var result *entities.User
+	err := repo.client.inTx(tracerCtx, func(queries *Queries) (err error) {
+		result, err = txUpdate(tracerCtx, queries, id, fields, version)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) UpdateBatch(ctx context.Context, ids []string, fields map[string]interface{}) (*entities.BatchChange, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-UpdateBatch")
+	defer span.End()
+
+	return repo.changeBatch(tracerCtx, ids, entities.HistoryUpdated, func(queries *Queries, id string) (User, error) {
+		arg := toUpdateUserParams(id, fields)
+		arg.ExpectedVersion = entities.AnyVersion
+
+		row, err := queries.UpdateUser(tracerCtx, arg)
+		return row, toDomainError(err)
+	})
+}
+
+func (repo *Repository) Remove(ctx context.Context, id string, version int64) error {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Remove")
+	defer span.End()
+
+	return repo.client.inTx(tracerCtx, func(queries *Queries) error {
+		return txRemove(tracerCtx, queries, id, version)
+	})
+}
+
+func (repo *Repository) RemoveBatch(ctx context.Context, ids []string) (*entities.BatchChange, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-RemoveBatch")
+	defer span.End()
+
+	return repo.changeBatch(tracerCtx, ids, entities.HistoryDeleted, func(queries *Queries, id string) (User, error) {
+		return queries.DeleteUser(tracerCtx, DeleteUserParams{
+			DeletedAt:       formatTime(time.Now()),
+			ID:              id,
+			ExpectedVersion: entities.AnyVersion,
+		})
+	})
+}
+
+// changeBatch applies change to each user that is not in the trash and records their history,
+// all in one transaction. The changed users are returned in the order of ids.
+func (repo *Repository) changeBatch(ctx context.Context, ids []string, action string, change func(*Queries, string) (User, error)) (*entities.BatchChange, error) {
+	result := &entities.BatchChange{Users: []*entities.User{}, NotFound: []string{}}
+
+	err := repo.client.inTx(ctx, func(queries *Queries) error {
+		rows, err := queries.FindUsers(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		after := make(map[string]*entities.User, len(rows))
+		for _, row := range rows {
+			before, err := toUser(row)
+			if err != nil {
+				return err
+			}
+
+			changed, err := change(queries, row.ID)
+			if err != nil {
+				return err
+			}
+
+			user, err := toUser(changed)
+			if err != nil {
+				return err
+			}
+			after[user.ID] = user
+
+			if err = recordHistory(ctx, queries, action, before, user); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range ids {
+			if user, ok := after[id]; ok {
+				result.Users = append(result.Users, user)
+			} else {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetDeleted(ctx context.Context) ([]*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-GetDeleted")
+	defer span.End()
+
+	rows, err := repo.client.queries.ListDeletedUsers(tracerCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("repo.sqlite.rows.count", len(rows)))
+
+	return toUserList(rows)
+}
+
+func (repo *Repository) Restore(ctx context.Context, id string) (*entities.User, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Restore")
+	defer span.End()
+
+	var result *entities.User
+	err := repo.client.inTx(tracerCtx, func(queries *Queries) error {
+		row, err := queries.FindUser(tracerCtx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domainErrors.AppUserNotFound
+			}
+			return err
+		}
+
+		if !row.DeletedAt.Valid {
+			return domainErrors.AppUserNotFound
+		}
+
+		before, err := toUser(row)
+		if err != nil {
+			return err
+		}
+
+		restored, err := queries.RestoreUser(tracerCtx, RestoreUserParams{
+			UpdatedAt: formatTime(time.Now()),
+			ID:        id,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result, err = toUser(restored); err != nil {
+			return err
+		}
+
+		return recordHistory(tracerCtx, queries, entities.HistoryRestored, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) Purge(ctx context.Context, id string) error {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-Purge")
+	defer span.End()
+
+	return repo.client.inTx(tracerCtx, func(queries *Queries) error {
+		row, err := queries.FindUser(tracerCtx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domainErrors.AppUserNotFound
+			}
+			return err
+		}
+
+		if !row.DeletedAt.Valid {
+			return domainErrors.AppUserNotDeleted
+		}
+
+		_, err = queries.HardDeleteUser(tracerCtx, id)
+		return err
+	})
+}
+
+func (repo *Repository) GetHistory(ctx context.Context, id string) ([]*entities.UserHistory, error) {
+	tracerCtx, span := repo.tracer.Start(ctx, "SQLiteRepository-GetHistory")
+	defer span.End()
+
+	// Users in the trash still exist, so their history is listed; purged users no longer do.
+	if _, err := repo.client.queries.FindUser(tracerCtx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainErrors.AppUserNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := repo.client.queries.ListUserHistory(tracerCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("repo.sqlite.rows.count", len(rows)))
+
+	history := make([]*entities.UserHistory, len(rows))
+	for i, row := range rows {
+		if history[i], err = toUserHistory(row); err != nil {
+			return nil, err
+		}
+	}
+
+	return history, nil
+}
+
+func txGetByID(ctx context.Context, queries *Queries, ids []string) ([]*entities.User, error) {
+	rows, err := queries.GetUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return toUserList(rows)
+}
+
+func txSave(ctx context.Context, queries *Queries, user *entities.User) (*entities.User, error) {
+	row, err := queries.CreateUser(ctx, toSaveUserParams(user))
+	if err != nil {
+		return nil, toDomainError(err)
+	}
+
+	result, err := toUser(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordHistory(ctx, queries, entities.HistoryCreated, nil, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func txUpdate(ctx context.Context, queries *Queries, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
+	arg := toUpdateUserParams(id, fields)
+	arg.ExpectedVersion = version
+
+	before, err := findActiveUser(ctx, queries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := queries.UpdateUser(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainErrors.AppVersionMismatch
+		}
+		return nil, toDomainError(err)
+	}
+
+	result, err := toUser(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordHistory(ctx, queries, entities.HistoryUpdated, before, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func txRemove(ctx context.Context, queries *Queries, id string, version int64) error {
+	before, err := findActiveUser(ctx, queries, id)
+	if err != nil {
+		return err
+	}
+
+	row, err := queries.DeleteUser(ctx, DeleteUserParams{
+		DeletedAt:       formatTime(time.Now()),
+		ID:              id,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainErrors.AppVersionMismatch
+		}
+		return err
+	}
+
+	after, err := toUser(row)
+	if err != nil {
+		return err
+	}
+
+	return recordHistory(ctx, queries, entities.HistoryDeleted, before, after)
+}
+
+// toDomainError maps the constraint violations the domain has an error for.
+func toDomainError(err error) error {
+	var sqliteErr *driver.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return domainErrors.AppUserExists
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		if strings.Contains(sqliteErr.Error(), emailIndex) {
+			return domainErrors.AppEmailTaken
+		}
+	}
+
+	return err
+}
+
+// findActiveUser reads a user that is not in the trash. Transactions hold the write lock of the
+// database, so the user cannot change until the transaction ends.
+func findActiveUser(ctx context.Context, queries *Queries, id string) (*entities.User, error) {
+	row, err := queries.FindUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainErrors.AppUserNotFound
+		}
+		return nil, err
+	}
+
+	if row.DeletedAt.Valid {
+		return nil, domainErrors.AppUserNotFound
+	}
+
+	return toUser(row)
+}
+
+func toUserList(rows []User) ([]*entities.User, error) {
+	users := make([]*entities.User, len(rows))
+	for i, row := range rows {
+		user, err := toUser(row)
+		if err != nil {
+			return nil, err
+		}
+		users[i] = user
+	}
+	return users, nil
+}
+
+func toUser(row User) (*entities.User, error) {
+	var user entities.User
+
+	var birth *time.Time
+	if row.Birth.Valid {
+		value, err := time.Parse(dateLayout, row.Birth.String)
+		if err != nil {
+			return nil, err
+		}
+		birth = &value
+	}
+
+	var email *string
+	if row.Email.Valid {
+		email = &row.Email.String
+	}
+
+	var location *string
+	if row.Location.Valid {
+		location = &row.Location.String
+	}
+
+	createdAt, err := parseTime(row.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAt, err := parseTime(row.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletedAt *time.Time
+	if row.DeletedAt.Valid {
+		value, err := parseTime(row.DeletedAt.String)
+		if err != nil {
+			return nil, err
+		}
+		deletedAt = &value
+	}
+
+	user.ID = row.ID
+	user.Name = row.Name
+	user.Birth = birth
+	user.Email = email
+	user.Location = location
+	user.CreatedAt = createdAt
+	user.UpdatedAt = updatedAt
+	user.Active = row.Active
+	user.Version = row.Version
+	user.DeletedAt = deletedAt
+
+	return &user, nil
+}
+
+func toSaveUserParams(user *entities.User) CreateUserParams {
+	var birth sql.NullString
+	if user.Birth != nil {
+		birth.String = user.Birth.Format(dateLayout)
+		birth.Valid = true
+	}
+
+	var email sql.NullString
+	if user.Email != nil {
+		email.String = entities.NormalizeEmail(*user.Email)
+		email.Valid = true
+	}
+
+	var location sql.NullString
+	if user.Location != nil {
+		location.String = *user.Location
+		location.Valid = true
+	}
+
+	return CreateUserParams{
+		ID:        user.ID,
+		Name:      user.Name,
+		Birth:     birth,
+		Email:     email,
+		Location:  location,
+		CreatedAt: formatTime(time.Now()),
+		Active:    user.Active,
+	}
+}
+
+func toUpdateUserParams(id string, fields map[string]interface{}) UpdateUserParams {
+	var row UpdateUserParams
+
+	// ID
+	row.ID = id
+	row.UpdatedAt = formatTime(time.Now())
+
+	// Name
+	if value, ok := fields["name"]; ok {
+		row.NameDoUpdate = true
+		row.Name = value.(string)
+	}
+
+	// Birth
+	if value, ok := fields["birth"]; ok {
+		row.BirthDoUpdate = true
+		if value != nil {
+			row.Birth.String = value.(time.Time).Format(dateLayout)
+			row.Birth.Valid = true
+		}
+	}
+
+	// Email
+	if value, ok := fields["email"]; ok {
+		row.EmailDoUpdate = true
+		if value != nil {
+			row.Email.String = entities.NormalizeEmail(value.(string))
+			row.Email.Valid = true
+		}
+	}
+
+	// Location
+	if value, ok := fields["location"]; ok {
+		row.LocationDoUpdate = true
+		if value != nil {
+			row.Location.String = value.(string)
+			row.Location.Valid = true
+		}
+	}
+
+	// Active
+	if value, ok := fields["active"]; ok {
+		row.ActiveDoUpdate = true
+		row.Active = value.(bool)
+	}
+
+	return row
+}
+
+// formatTime stores a timestamp in UTC with microseconds, so that stored timestamps sort as text.
+func formatTime(value time.Time) string {
+	return value.UTC().Format(timeLayout)
+}
+
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(timeLayout, value)
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"users/domain"
+	"users/infrastructure/storagetest"
+)
+
+func TestRepository(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Repository {
+		repo, err := NewRepository(newTestClient(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestUnitOfWork(t *testing.T) {
+	storagetest.RunUnitOfWork(t, func(t *testing.T) (storagetest.Repository, domain.UnitOfWork) {
+		client := newTestClient(t)
+
+		repo, err := NewRepository(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		unitOfWork, err := NewUnitOfWork(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo, unitOfWork
+	})
+}
+
+// newTestClient opens a migrated database in a temporary directory.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	config, err := NewConfig(filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+
+	// The migrations are read relative to the root of the module.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if err = client.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"users/domain"
+	"users/domain/entities"
+)
+
+// UnitOfWork runs the repository functions of domain.Tx inside a single SQLite transaction.
+type UnitOfWork struct {
+	client *Client
+	tracer trace.Tracer
+}
+
+func NewUnitOfWork(client *Client) (*UnitOfWork, error) {
+	return &UnitOfWork{
+		client: client,
+		tracer: otel.Tracer("SQLiteUnitOfWork"),
+	}, nil
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(domain.Tx) error) error {
+	tracerCtx, span := u.tracer.Start(ctx, "SQLiteUnitOfWork-Do")
+	defer span.End()
+
+	return u.client.inTx(tracerCtx, func(queries *Queries) error {
+		return fn(newTx(queries))
+	})
+}
+
+// newTx binds the repository functions to the queries of an open transaction.
+func newTx(queries *Queries) domain.Tx {
+	return domain.Tx{
+		GetByID: func(ctx context.Context, ids []string) ([]*entities.User, error) {
+			return txGetByID(ctx, queries, ids)
+		},
+		Save: func(ctx context.Context, user *entities.User) (*entities.User, error) {
+			return txSave(ctx, queries, user)
+		},
+		Update: func(ctx context.Context, id string, fields map[string]interface{}, version int64) (*entities.User, error) {
+			return txUpdate(ctx, queries, id, fields, version)
+		},
+		Remove: func(ctx context.Context, id string, version int64) error {
+			return txRemove(ctx, queries, id, version)
+		},
+	}
+}
@@ -0,0 +1,472 @@
+// Package storagetest holds the conformance suite every storage backend must pass.
+package storagetest
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"users/domain"
+	"users/domain/entities"
+	"users/domain/errors"
+)
+
+// Repository holds the functions of a storage covered by the suite.
+type Repository interface {
+	Get(context.Context, entities.UserFilter, entities.PageRequest) (*entities.Page, error)
+	GetByID(context.Context, []string) ([]*entities.User, error)
+	Save(context.Context, *entities.User) (*entities.User, error)
+	SaveBatch(context.Context, []*entities.User, bool) ([]*entities.BatchResult, error)
+	Update(context.Context, string, map[string]interface{}, int64) (*entities.User, error)
+	Remove(context.Context, string, int64) error
+	Purge(context.Context, string) error
+	GetHistory(context.Context, string) ([]*entities.UserHistory, error)
+}
+
+// Run runs the suite. newRepository must return an empty repository on every call.
+func Run(t *testing.T, newRepository func(t *testing.T) Repository) {
+	tests := []struct {
+		name string
+		test func(*testing.T, Repository)
+	}{
+		{name: "save and get by ID", test: testSave},
+		{name: "save existing ID", test: testSaveExisting},
+		{name: "save batch", test: testSaveBatch},
+		{name: "save atomic batch", test: testSaveAtomicBatch},
+		{name: "update", test: testUpdate},
+		{name: "update missing user", test: testUpdateMissing},
+		{name: "update with stale version", test: testUpdateStale},
+		{name: "remove", test: testRemove},
+		{name: "remove missing user", test: testRemoveMissing},
+		{name: "purge", test: testPurge},
+		{name: "history", test: testHistory},
+		{name: "null fields", test: testNullFields},
+		{name: "get reads every field", test: testGetFields},
+		{name: "get filters by active", test: testGetActive},
+		{name: "get with cursor of another sort", test: testGetCursorSort},
+		{name: "updated_at advances", test: testUpdatedAt},
+		{name: "unique email", test: testUniqueEmail},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, newRepository(t))
+		})
+	}
+}
+
+// RunUnitOfWork runs the part of the suite that goes through transactions. newStorage must return an empty
+// repository and a unit of work over the same data on every call.
+func RunUnitOfWork(t *testing.T, newStorage func(t *testing.T) (Repository, domain.UnitOfWork)) {
+	tests := []struct {
+		name string
+		test func(*testing.T, Repository, domain.UnitOfWork)
+	}{
+		{name: "update in transaction", test: testTxUpdate},
+		{name: "remove in transaction", test: testTxRemove},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo, unitOfWork := newStorage(t)
+			test.test(t, repo, unitOfWork)
+		})
+	}
+}
+
+func testSave(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := newUser("Ann")
+	user.Birth = date(1992, 9, 23)
+	user.Email = text("ann@test.com")
+	user.Location = text("Bogotá")
+
+	saved, err := repo.Save(ctx, user)
+	require.NoError(t, err)
+
+	assertUser(t, user, saved)
+	assert.Equal(t, int64(1), saved.Version)
+	assert.False(t, saved.CreatedAt.IsZero())
+	assert.Nil(t, saved.DeletedAt)
+
+	found, err := repo.GetByID(ctx, []string{user.ID, uuid.New().String()})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assertUser(t, user, found[0])
+}
+
+func testSaveExisting(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := newUser("Ann")
+
+	_, err := repo.Save(ctx, user)
+	require.NoError(t, err)
+
+	_, err = repo.Save(ctx, user)
+	assert.ErrorIs(t, err, errors.AppUserExists)
+}
+
+func testSaveBatch(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	existing := newUser("Ann")
+	existing.Email = text("ann@test.com")
+	existing = save(t, repo, existing)
+
+	bob := newUser("Bob")
+	sameID := newUser("Cid")
+	sameID.ID = existing.ID
+	sameEmail := newUser("Dan")
+	sameEmail.Email = text("ANN@test.com")
+	repeated := newUser("Eve")
+	repeated.ID = bob.ID
+	fay := newUser("Fay")
+
+	results, err := repo.SaveBatch(ctx, []*entities.User{bob, sameID, sameEmail, repeated, fay}, false)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, errors.AppUserExists)
+	assert.ErrorIs(t, results[2].Err, errors.AppEmailTaken)
+	assert.ErrorIs(t, results[3].Err, errors.AppUserExists)
+	assert.NoError(t, results[4].Err)
+
+	found, err := repo.GetByID(ctx, []string{existing.ID, bob.ID, fay.ID})
+	require.NoError(t, err)
+	assert.Len(t, found, 3)
+}
+
+func testSaveAtomicBatch(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	existing := save(t, repo, newUser("Ann"))
+
+	bob := newUser("Bob")
+	sameID := newUser("Cid")
+	sameID.ID = existing.ID
+
+	results, err := repo.SaveBatch(ctx, []*entities.User{bob, sameID}, true)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.ErrorIs(t, results[0].Err, errors.AppBatchAborted)
+	assert.ErrorIs(t, results[1].Err, errors.AppUserExists)
+
+	found, err := repo.GetByID(ctx, []string{bob.ID})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testUpdate(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+
+	updated, err := repo.Update(ctx, user.ID, map[string]interface{}{
+		"name":     "Anna",
+		"location": "Cali",
+		"active":   false,
+	}, user.Version)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Anna", updated.Name)
+	assert.Equal(t, text("Cali"), updated.Location)
+	assert.False(t, updated.Active)
+	assert.Equal(t, user.Version+1, updated.Version)
+
+	found, err := repo.GetByID(ctx, []string{user.ID})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assertUser(t, updated, found[0])
+}
+
+func testUpdateMissing(t *testing.T, repo Repository) {
+	_, err := repo.Update(context.Background(), uuid.New().String(), map[string]interface{}{"name": "Anna"}, entities.AnyVersion)
+	assert.ErrorIs(t, err, errors.AppUserNotFound)
+}
+
+func testUpdateStale(t *testing.T, repo Repository) {
+	user := save(t, repo, newUser("Ann"))
+
+	_, err := repo.Update(context.Background(), user.ID, map[string]interface{}{"name": "Anna"}, user.Version+1)
+	assert.ErrorIs(t, err, errors.AppVersionMismatch)
+}
+
+func testRemove(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+
+	require.NoError(t, repo.Remove(ctx, user.ID, user.Version))
+
+	found, err := repo.GetByID(ctx, []string{user.ID})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	_, err = repo.Update(ctx, user.ID, map[string]interface{}{"name": "Anna"}, entities.AnyVersion)
+	assert.ErrorIs(t, err, errors.AppUserNotFound)
+
+	assert.ErrorIs(t, repo.Remove(ctx, user.ID, entities.AnyVersion), errors.AppUserNotFound)
+}
+
+func testRemoveMissing(t *testing.T, repo Repository) {
+	err := repo.Remove(context.Background(), uuid.New().String(), entities.AnyVersion)
+	assert.ErrorIs(t, err, errors.AppUserNotFound)
+}
+
+func testPurge(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+
+	assert.ErrorIs(t, repo.Purge(ctx, user.ID), errors.AppUserNotDeleted)
+	assert.ErrorIs(t, repo.Purge(ctx, uuid.New().String()), errors.AppUserNotFound)
+
+	require.NoError(t, repo.Remove(ctx, user.ID, entities.AnyVersion))
+	require.NoError(t, repo.Purge(ctx, user.ID))
+
+	assert.ErrorIs(t, repo.Purge(ctx, user.ID), errors.AppUserNotFound)
+}
+
+func testHistory(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+
+	_, err := repo.GetHistory(ctx, uuid.New().String())
+	assert.ErrorIs(t, err, errors.AppUserNotFound)
+
+	require.NoError(t, repo.Remove(ctx, user.ID, user.Version))
+
+	history, err := repo.GetHistory(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, entities.HistoryDeleted, history[1].Action)
+
+	require.NoError(t, repo.Purge(ctx, user.ID))
+
+	_, err = repo.GetHistory(ctx, user.ID)
+	assert.ErrorIs(t, err, errors.AppUserNotFound)
+}
+
+func testNullFields(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+
+	assert.Nil(t, user.Birth)
+	assert.Nil(t, user.Email)
+	assert.Nil(t, user.Location)
+
+	updated, err := repo.Update(ctx, user.ID, map[string]interface{}{
+		"birth":    *date(1992, 9, 23),
+		"email":    "ann@test.com",
+		"location": "Cali",
+	}, entities.AnyVersion)
+	require.NoError(t, err)
+
+	assert.Equal(t, date(1992, 9, 23).Format(time.DateOnly), updated.Birth.Format(time.DateOnly))
+	assert.Equal(t, text("ann@test.com"), updated.Email)
+	assert.Equal(t, text("Cali"), updated.Location)
+
+	cleared, err := repo.Update(ctx, user.ID, map[string]interface{}{
+		"birth":    nil,
+		"email":    nil,
+		"location": nil,
+	}, entities.AnyVersion)
+	require.NoError(t, err)
+
+	assert.Nil(t, cleared.Birth)
+	assert.Nil(t, cleared.Email)
+	assert.Nil(t, cleared.Location)
+	assert.Equal(t, "Ann", cleared.Name)
+}
+
+func testGetActive(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	active := save(t, repo, newUser("Ann"))
+	inactive := newUser("Bob")
+	inactive.Active = false
+	inactive = save(t, repo, inactive)
+	removed := save(t, repo, newUser("Cid"))
+	require.NoError(t, repo.Remove(ctx, removed.ID, entities.AnyVersion))
+
+	tests := []struct {
+		name   string
+		active *bool
+		want   []string
+	}{
+		{name: "without filter", active: nil, want: []string{active.ID, inactive.ID}},
+		{name: "only active", active: flag(true), want: []string{active.ID}},
+		{name: "only inactive", active: flag(false), want: []string{inactive.ID}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := repo.Get(ctx, entities.UserFilter{Active: test.active}, entities.PageRequest{Limit: 10, Sort: entities.DefaultSort})
+			require.NoError(t, err)
+
+			ids := make([]string, len(page.Users))
+			for i, user := range page.Users {
+				ids[i] = user.ID
+			}
+			assert.Equal(t, test.want, ids)
+			assert.Nil(t, page.Next)
+		})
+	}
+}
+
+func testGetFields(t *testing.T, repo Repository) {
+	user := newUser("Ann")
+	user.Birth = date(1992, 9, 23)
+	user.Email = text("ann@test.com")
+	user.Location = text("Bogotá")
+	saved := save(t, repo, user)
+
+	page, err := repo.Get(context.Background(), entities.UserFilter{}, entities.PageRequest{Limit: 10, Sort: entities.DefaultSort})
+	require.NoError(t, err)
+	require.Len(t, page.Users, 1)
+
+	got := page.Users[0]
+	assertUser(t, user, got)
+	assert.Equal(t, saved.Version, got.Version)
+	assert.True(t, saved.CreatedAt.Equal(got.CreatedAt), "created_at %s, want %s", got.CreatedAt, saved.CreatedAt)
+	assert.True(t, saved.UpdatedAt.Equal(got.UpdatedAt), "updated_at %s, want %s", got.UpdatedAt, saved.UpdatedAt)
+	assert.Nil(t, got.DeletedAt)
+}
+
+func testGetCursorSort(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	save(t, repo, newUser("Ann"))
+	save(t, repo, newUser("Bob"))
+
+	byName := []entities.SortKey{{Field: entities.SortByName}}
+	page, err := repo.Get(ctx, entities.UserFilter{}, entities.PageRequest{Limit: 1, Sort: byName})
+	require.NoError(t, err)
+	require.NotNil(t, page.Next)
+
+	next, err := repo.Get(ctx, entities.UserFilter{}, entities.PageRequest{Limit: 1, Sort: byName, After: page.Next})
+	require.NoError(t, err)
+	require.Len(t, next.Users, 1)
+	assert.Equal(t, "Bob", next.Users[0].Name)
+
+	for _, sort := range [][]entities.SortKey{{{Field: entities.SortByEmail}}, {{Field: entities.SortByCreatedAt}}} {
+		_, err = repo.Get(ctx, entities.UserFilter{}, entities.PageRequest{Limit: 1, Sort: sort, After: page.Next})
+		assert.ErrorIs(t, err, errors.AppInvalidCursor, entities.FormatSort(sort))
+	}
+}
+
+func testUpdatedAt(t *testing.T, repo Repository) {
+	user := save(t, repo, newUser("Ann"))
+	assert.Equal(t, user.CreatedAt, user.UpdatedAt)
+
+	time.Sleep(5 * time.Millisecond)
+
+	updated, err := repo.Update(context.Background(), user.ID, map[string]interface{}{"name": "Anna"}, user.Version)
+	require.NoError(t, err)
+
+	assert.Equal(t, user.CreatedAt, updated.CreatedAt)
+	assert.True(t, updated.UpdatedAt.After(user.UpdatedAt), "updated_at %s is not after %s", updated.UpdatedAt, user.UpdatedAt)
+}
+
+func testUniqueEmail(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	ann := newUser("Ann")
+	ann.Email = text("Ann@Test.com")
+	ann = save(t, repo, ann)
+
+	assert.Equal(t, text("ann@test.com"), ann.Email)
+
+	taken := newUser("Bob")
+	taken.Email = text("ANN@test.com")
+	_, err := repo.Save(ctx, taken)
+	assert.ErrorIs(t, err, errors.AppEmailTaken)
+
+	bob := save(t, repo, newUser("Bob"))
+	_, err = repo.Update(ctx, bob.ID, map[string]interface{}{"email": "ann@TEST.com"}, entities.AnyVersion)
+	assert.ErrorIs(t, err, errors.AppEmailTaken)
+
+	_, err = repo.Update(ctx, ann.ID, map[string]interface{}{"email": "ANN@test.com"}, entities.AnyVersion)
+	assert.NoError(t, err)
+}
+
+// testTxUpdate checks that the transaction itself rejects missing, trashed and stale users, since the
+// actions do not read them first.
+func testTxUpdate(t *testing.T, repo Repository, unitOfWork domain.UnitOfWork) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+	trashed := save(t, repo, newUser("Bob"))
+	require.NoError(t, repo.Remove(ctx, trashed.ID, trashed.Version))
+
+	update := func(id string, version int64) error {
+		return unitOfWork.Do(ctx, func(tx domain.Tx) error {
+			_, err := tx.Update(ctx, id, map[string]interface{}{"name": "Anna"}, version)
+			return err
+		})
+	}
+
+	assert.ErrorIs(t, update(uuid.New().String(), entities.AnyVersion), errors.AppUserNotFound)
+	assert.ErrorIs(t, update(trashed.ID, entities.AnyVersion), errors.AppUserNotFound)
+	assert.ErrorIs(t, update(user.ID, user.Version+1), errors.AppVersionMismatch)
+	require.NoError(t, update(user.ID, user.Version))
+
+	found, err := repo.GetByID(ctx, []string{user.ID})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Anna", found[0].Name)
+}
+
+func testTxRemove(t *testing.T, repo Repository, unitOfWork domain.UnitOfWork) {
+	ctx := context.Background()
+	user := save(t, repo, newUser("Ann"))
+
+	remove := func(id string, version int64) error {
+		return unitOfWork.Do(ctx, func(tx domain.Tx) error {
+			return tx.Remove(ctx, id, version)
+		})
+	}
+
+	assert.ErrorIs(t, remove(uuid.New().String(), entities.AnyVersion), errors.AppUserNotFound)
+	assert.ErrorIs(t, remove(user.ID, user.Version+1), errors.AppVersionMismatch)
+	require.NoError(t, remove(user.ID, user.Version))
+	assert.ErrorIs(t, remove(user.ID, entities.AnyVersion), errors.AppUserNotFound)
+}
+
+func newUser(name string) *entities.User {
+	return &entities.User{ID: uuid.New().String(), Name: name, Active: true}
+}
+
+func save(t *testing.T, repo Repository, user *entities.User) *entities.User {
+	t.Helper()
+
+	saved, err := repo.Save(context.Background(), user)
+	require.NoError(t, err)
+
+	return saved
+}
+
+// assertUser compares the fields a client sets.
+func assertUser(t *testing.T, want *entities.User, got *entities.User) {
+	t.Helper()
+
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.Name, got.Name)
+	assert.Equal(t, want.Email, got.Email)
+	assert.Equal(t, want.Location, got.Location)
+	assert.Equal(t, want.Active, got.Active)
+
+	if want.Birth == nil {
+		assert.Nil(t, got.Birth)
+	} else if assert.NotNil(t, got.Birth) {
+		assert.Equal(t, want.Birth.Format(time.DateOnly), got.Birth.Format(time.DateOnly))
+	}
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	value := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &value
+}
+
+func text(value string) *string {
+	return &value
+}
+
+func flag(value bool) *bool {
+	return &value
+}
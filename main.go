@@ -7,7 +7,6 @@ import (
 	"os/signal"
 	"users/docs"
 	"users/infrastructure/dependencies"
-	"users/infrastructure/postgres"
 	"users/infrastructure/server"
 )
 
@@ -47,23 +46,21 @@ func main() {
 	// Swagger
 	docs.SwaggerInfo.BasePath = config.Server.Prefix
 
-	// Postgres client.
-	postgresClient, err := postgres.NewClient(config.DB)
-	if err != nil {
-		errorLog.Printf("Database error: %s", err.Error())
-		return
+	// Storage, migrated if it needs to.
+	infoLog.Printf("Using storage: %s", config.Storage)
+	if config.Storage.Driver == dependencies.DriverMemory {
+		infoLog.Println("Using in-memory storage, data is lost on exit")
 	}
-	defer postgresClient.Close()
 
-	// Run migrations
-	err = postgresClient.Migrate()
+	storage, err := dependencies.OpenStorage(config.Storage)
 	if err != nil {
-		errorLog.Printf("Database migration error: %s", err.Error())
+		errorLog.Printf("Storage error: %s", err.Error())
 		return
 	}
+	defer storage.Close()
 
-	// Link actions
-	actions, err := dependencies.NewActions(postgresClient)
+	// Link actions to the storage.
+	actions, err := dependencies.NewActions(storage.Repository, storage.UnitOfWork)
 	if err != nil {
 		errorLog.Printf("Actions error: %s", err.Error())
 		return
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS users_version_trigger ON users;
+DROP FUNCTION IF EXISTS increment_version_column();
+ALTER TABLE users DROP COLUMN IF EXISTS version;
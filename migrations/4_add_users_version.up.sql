@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE FUNCTION increment_version_column() RETURNS trigger AS $$
+    BEGIN
+        NEW.version = OLD.version + 1;
+        RETURN NEW;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_version_trigger
+    BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
@@ -0,0 +1,14 @@
+CREATE TABLE user_history
+(
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        CHARACTER(36) NOT NULL,
+    action         TEXT      NOT NULL,
+    before         JSONB,
+    after          JSONB,
+    changed_fields TEXT[]    NOT NULL,
+    actor          TEXT,
+    trace_id       TEXT,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX user_history_user_id_idx ON user_history (user_id, created_at);
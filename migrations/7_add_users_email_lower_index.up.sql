@@ -0,0 +1,31 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+-- Emails that only differ by case cannot be merged automatically, so the migration stops and lists them.
+DO $$
+    DECLARE
+        conflicts TEXT;
+    BEGIN
+        SELECT string_agg(format('%s (%s)', lower(email), ids), '; ')
+        INTO conflicts
+        FROM (SELECT email, string_agg(id, ', ' ORDER BY created_at, id) AS ids
+              FROM (SELECT lower(email) AS email, id, created_at FROM users WHERE email IS NOT NULL) AS emails
+              GROUP BY email
+              HAVING count(*) > 1) AS duplicates;
+
+        IF conflicts IS NOT NULL THEN
+            RAISE EXCEPTION 'users share an email that only differs by case: %', conflicts
+                USING HINT = 'Change or clear the email of all but one user of each group and run the migration again.';
+        END IF;
+    END;
+$$;
+
+-- The case of an email carries no meaning, so lowercasing bumps neither the version nor updated_at and issued ETags stay valid.
+ALTER TABLE users DISABLE TRIGGER users_version_trigger;
+ALTER TABLE users DISABLE TRIGGER users_updated_at_trigger;
+
+UPDATE users SET email = lower(email) WHERE email <> lower(email);
+
+ALTER TABLE users ENABLE TRIGGER users_version_trigger;
+ALTER TABLE users ENABLE TRIGGER users_updated_at_trigger;
+
+CREATE UNIQUE INDEX users_email_lower_idx ON users (lower(email));
@@ -0,0 +1,19 @@
+-- Timestamps are stored as UTC text with microseconds, and birth dates as YYYY-MM-DD, so that
+-- comparing them as strings orders them in time.
+CREATE TABLE users
+(
+    id         CHARACTER(36) PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    birth      TEXT,
+    email      TEXT,
+    location   TEXT,
+    created_at TEXT    NOT NULL,
+    updated_at TEXT    NOT NULL,
+    active     BOOLEAN NOT NULL,
+    version    INTEGER NOT NULL DEFAULT 1,
+    deleted_at TEXT
+);
+
+CREATE UNIQUE INDEX users_email_lower_idx ON users (lower(email));
+
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
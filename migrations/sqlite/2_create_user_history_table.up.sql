@@ -0,0 +1,14 @@
+CREATE TABLE user_history
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id        CHARACTER(36) NOT NULL,
+    action         TEXT NOT NULL,
+    before         TEXT,
+    after          TEXT,
+    changed_fields TEXT NOT NULL,
+    actor          TEXT,
+    trace_id       TEXT,
+    created_at     TEXT NOT NULL
+);
+
+CREATE INDEX user_history_user_id_idx ON user_history (user_id, created_at);
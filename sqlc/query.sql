@@ -1,20 +1,44 @@
 -- name: GetUser :one
 SELECT * FROM users
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: LockUser :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
 
 -- name: GetUsers :many
 SELECT * FROM users
-WHERE id = ANY($1::text[]);
+WHERE id = ANY($1::text[]) AND deleted_at IS NULL;
 
 -- name: ListUsers :many
 SELECT * FROM users
+WHERE deleted_at IS NULL
 ORDER BY name;
 
 -- name: ListActiveUsers :many
 SELECT * FROM users
-WHERE active
+WHERE active AND deleted_at IS NULL
 ORDER BY name;
 
+-- name: ListDeletedUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (@query::text <% name
+   OR @query::text <% email
+   OR @query::text <% location)
+ORDER BY GREATEST(
+    word_similarity(@query::text, name),
+    word_similarity(@query::text, COALESCE(email, '')),
+    word_similarity(@query::text, COALESCE(location, ''))
+) DESC, name, id
+LIMIT @max_results;
+
 -- name: CreateUser :one
 INSERT INTO users (
   id, name, birth, email, location, active
@@ -23,6 +47,34 @@ INSERT INTO users (
 )
 RETURNING *;
 
+-- name: CreateUsers :batchone
+INSERT INTO users (
+  id, name, birth, email, location, active
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: CreateUsersSkippingConflicts :batchone
+INSERT INTO users (
+  id, name, birth, email, location, active
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: FindUserIDs :many
+SELECT id FROM users
+WHERE id = ANY(CAST(@ids::text[] AS bpchar[]));
+
+-- name: LockUsers :many
+SELECT * FROM users
+WHERE id = ANY(CAST(@ids::text[] AS bpchar[]))
+  AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE;
+
 -- name: UpdateUser :one
 UPDATE users
 SET
@@ -41,8 +93,71 @@ SET
   active = CASE WHEN @active_do_update::boolean
   THEN @active ELSE active END
 WHERE id = $1
+  AND deleted_at IS NULL
+  AND (@expected_version::bigint = 0 OR version = @expected_version)
+RETURNING *;
+
+-- name: UpdateUsers :many
+UPDATE users
+SET
+  name = CASE WHEN @name_do_update::boolean
+  THEN @name ELSE name END,
+
+  birth = CASE WHEN @birth_do_update::boolean
+  THEN @birth ELSE birth END,
+
+  email = CASE WHEN @email_do_update::boolean
+  THEN @email ELSE email END,
+
+  location = CASE WHEN @location_do_update::boolean
+  THEN @location ELSE location END,
+
+  active = CASE WHEN @active_do_update::boolean
+  THEN @active ELSE active END
+WHERE id = ANY(CAST(@ids::text[] AS bpchar[]))
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteUser :one
+UPDATE users
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND (@expected_version::bigint = 0 OR version = @expected_version)
+RETURNING *;
+
+-- name: DeleteUsers :many
+UPDATE users
+SET deleted_at = NOW()
+WHERE id = ANY(CAST(@ids::text[] AS bpchar[]))
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeleteUser :exec
+-- name: HardDeleteUser :execrows
 DELETE FROM users
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: CreateUserHistory :exec
+INSERT INTO user_history (
+  user_id, action, before, after, changed_fields, actor, trace_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: CreateUserHistories :batchexec
+INSERT INTO user_history (
+  user_id, action, before, after, changed_fields, actor, trace_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ListUserHistory :many
+SELECT * FROM user_history
+WHERE user_id = $1
+ORDER BY created_at, id;
@@ -2,9 +2,38 @@ CREATE TABLE users (
     id         CHARACTER(36) PRIMARY KEY,
     name       TEXT      NOT NULL,
     birth      DATE,
-    email      TEXT UNIQUE,
+    email      TEXT,
     location   TEXT,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW(),
     active     BOOLEAN   NOT NULL
 );
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX users_name_trgm_idx ON users USING GIN (name gin_trgm_ops);
+CREATE INDEX users_email_trgm_idx ON users USING GIN (email gin_trgm_ops);
+CREATE INDEX users_location_trgm_idx ON users USING GIN (location gin_trgm_ops);
+
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE user_history
+(
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        CHARACTER(36) NOT NULL,
+    action         TEXT      NOT NULL,
+    before         JSONB,
+    after          JSONB,
+    changed_fields TEXT[]    NOT NULL,
+    actor          TEXT,
+    trace_id       TEXT,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX user_history_user_id_idx ON user_history (user_id, created_at);
+
+CREATE UNIQUE INDEX users_email_lower_idx ON users (lower(email));
@@ -0,0 +1,89 @@
+-- name: FindUser :one
+SELECT * FROM users
+WHERE id = ? LIMIT 1;
+
+-- name: GetUsers :many
+SELECT * FROM users
+WHERE id IN (sqlc.slice('ids')) AND deleted_at IS NULL;
+
+-- name: FindUsers :many
+SELECT * FROM users
+WHERE id IN (sqlc.slice('ids')) AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: ListDeletedUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (instr(lower(name), lower(@query)) > 0
+   OR instr(lower(COALESCE(email, '')), lower(@query)) > 0
+   OR instr(lower(COALESCE(location, '')), lower(@query)) > 0)
+ORDER BY name, id
+LIMIT @max_results;
+
+-- name: CreateUser :one
+INSERT INTO users (
+  id, name, birth, email, location, created_at, updated_at, active
+) VALUES (
+  @id, @name, @birth, @email, @location, @created_at, @created_at, @active
+)
+RETURNING *;
+
+-- name: UpdateUser :one
+UPDATE users
+SET
+  name = CASE WHEN CAST(@name_do_update AS BOOLEAN)
+  THEN @name ELSE name END,
+
+  birth = CASE WHEN CAST(@birth_do_update AS BOOLEAN)
+  THEN @birth ELSE birth END,
+
+  email = CASE WHEN CAST(@email_do_update AS BOOLEAN)
+  THEN @email ELSE email END,
+
+  location = CASE WHEN CAST(@location_do_update AS BOOLEAN)
+  THEN @location ELSE location END,
+
+  active = CASE WHEN CAST(@active_do_update AS BOOLEAN)
+  THEN @active ELSE active END,
+
+  updated_at = @updated_at,
+  version = version + 1
+WHERE id = @id
+  AND deleted_at IS NULL
+  AND (CAST(@expected_version AS INTEGER) = 0 OR version = @expected_version)
+RETURNING *;
+
+-- name: DeleteUser :one
+UPDATE users
+SET updated_at = @deleted_at, deleted_at = @deleted_at, version = version + 1
+WHERE id = @id
+  AND deleted_at IS NULL
+  AND (CAST(@expected_version AS INTEGER) = 0 OR version = @expected_version)
+RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, updated_at = @updated_at, version = version + 1
+WHERE id = @id AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: HardDeleteUser :execrows
+DELETE FROM users
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: CreateUserHistory :exec
+INSERT INTO user_history (
+  user_id, action, before, after, changed_fields, actor, trace_id, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListUserHistory :many
+SELECT * FROM user_history
+WHERE user_id = ?
+ORDER BY created_at, id;